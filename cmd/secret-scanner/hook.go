@@ -0,0 +1,86 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grab/secret-scanner/scanner"
+	"github.com/grab/secret-scanner/scanner/ignore"
+	"github.com/grab/secret-scanner/scanner/options"
+	"github.com/grab/secret-scanner/scanner/session"
+	"github.com/grab/secret-scanner/scanner/signatures"
+)
+
+const (
+	// HookCommand is the sub-command running the scanner as a git hook
+	HookCommand = "hook"
+
+	// HookPreCommit ...
+	HookPreCommit = "pre-commit"
+
+	// HookPreReceive ...
+	HookPreReceive = "pre-receive"
+)
+
+// runHook runs the scanner as a git hook, usage: secret-scanner hook <hook-type> [flags].
+// It exits with status 1 if secrets are found or the scan fails.
+func runHook(hookType string, args []string) {
+	// drop the sub-command so that the remaining flags are parsed as usual
+	os.Args = append([]string{os.Args[0]}, args...)
+	opt, err := options.Parse()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	dir := *opt.LocalPath
+	if dir == "" {
+		dir = "."
+	}
+
+	sess := &session.Session{}
+	sess.Initialize(opt)
+	defer sess.End()
+
+	var scanned, rejected string
+	switch hookType {
+	case HookPreCommit:
+		scanned, rejected = "the staged changes", "Commit aborted"
+		err = scanner.ScanStagedChanges(sess, dir)
+	case HookPreReceive:
+		scanned, rejected = "the pushed commits", "Push rejected"
+		var updates []*scanner.RefUpdate
+		updates, err = scanner.ParseRefUpdates(os.Stdin)
+		if err == nil {
+			err = scanner.ScanPushedCommits(sess, dir, updates)
+		}
+	default:
+		err = fmt.Errorf("invalid hook type %s (Currently supports %s, %s)", hookType, HookPreCommit, HookPreReceive)
+	}
+	if err != nil {
+		sess.Out.Error("secret-scanner %s: %s\n", hookType, err)
+		sess.End()
+		os.Exit(1)
+	}
+
+	if len(sess.Findings) == 0 {
+		return
+	}
+	// with -fail-on, findings of lower severity are reported without rejecting
+	if *opt.FailOn != "" && !sess.FailOnFindings() {
+		sess.Out.Warn("secret-scanner found %d potential %s below %s severity in %s:\n", len(sess.Findings), scanner.Pluralize(len(sess.Findings), "secret", "secrets"), *opt.FailOn, scanned)
+		scanner.PrintHookReport(sess)
+		return
+	}
+
+	sess.Out.Error("secret-scanner found %d potential %s in %s:\n", len(sess.Findings), scanner.Pluralize(len(sess.Findings), "secret", "secrets"), scanned)
+	scanner.PrintHookReport(sess)
+	sess.Out.Error("%s. Remove the secrets, or mark false positives with a %q comment or in %s.\n", rejected, signatures.AllowMarker, ignore.DefaultFilename)
+	sess.End()
+	os.Exit(1)
+}
@@ -6,11 +6,15 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
+	"os/signal"
 	"path"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -19,9 +23,16 @@ import (
 	"github.com/grab/secret-scanner/scanner/gitprovider"
 	"github.com/grab/secret-scanner/scanner/options"
 	"github.com/grab/secret-scanner/scanner/session"
+	"github.com/grab/secret-scanner/scanner/web"
 )
 
 func main() {
+	// Run as git hook
+	if len(os.Args) > 2 && os.Args[1] == HookCommand {
+		runHook(os.Args[2], os.Args[3:])
+		return
+	}
+
 	// Parse CLI options
 	opt, err := options.Parse()
 	if err != nil {
@@ -29,6 +40,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Validate output format
+	switch *opt.Format {
+	case session.FormatJSON, session.FormatSARIF, session.FormatHTML:
+	default:
+		fmt.Println("error: invalid output format (Currently supports json, sarif, html)")
+		os.Exit(1)
+	}
+
+	// Re-emit or serve a saved session without scanning
+	if *opt.Load != "" {
+		loadSession(opt)
+		return
+	}
+
 	// Load env file
 	loadEnv(*opt.EnvFilePath)
 
@@ -80,17 +105,42 @@ func main() {
 	sess.Out.Important("%s Scanning Started at %s\n", strings.Title(*opt.GitProvider), sess.Stats.StartedAt.Format(time.RFC3339))
 	sess.Out.Important("Loaded %d signatures\n", len(sess.Signatures))
 
-	if sess.Stats.Status == "finished" {
-		sess.Out.Important("Loaded session file: %s\n", *sess.Options.Load)
-		return
+	// Serve the UI while scanning
+	ctx := handleSignals(sess)
+	if *opt.UI {
+		go serveUI(sess)
 	}
 
 	// Scan
-	scanner.Scan(sess, gitProvider)
-	sess.Out.Important("Gitlab Scanning Finished at %s\n", sess.Stats.FinishedAt.Format(time.RFC3339))
+	scanner.Scan(ctx, sess, gitProvider)
+	if sess.Stats.Status == session.StatusCancelled {
+		sess.Out.Important("%s Scanning Cancelled at %s, saving partial results\n", strings.Title(*opt.GitProvider), sess.Stats.FinishedAt.Format(time.RFC3339))
+	} else {
+		sess.Out.Important("%s Scanning Finished at %s\n", strings.Title(*opt.GitProvider), sess.Stats.FinishedAt.Format(time.RFC3339))
+	}
+
+	finishSession(ctx, sess)
+}
+
+// loadSession restores a session saved with -output, to save it in another format or serve it in the UI
+func loadSession(opt options.Options) {
+	sess := &session.Session{}
+	sess.Initialize(opt)
+	sess.Out.Important("Loaded session file %s with %d %s\n", *opt.Load, len(sess.Findings), scanner.Pluralize(len(sess.Findings), "finding", "findings"))
 
+	ctx := handleSignals(sess)
+	if *opt.UI {
+		go serveUI(sess)
+	}
+
+	finishSession(ctx, sess)
+}
+
+// finishSession saves the report, serves the UI until interrupted,
+// and exits with status 1 if a finding reaches the -fail-on severity
+func finishSession(ctx context.Context, sess *session.Session) {
 	if *sess.Options.Report != "" {
-		absPath, err := sess.SaveToFile(*sess.Options.Report)
+		absPath, err := sess.SaveReportToFile(*sess.Options.Report)
 		if err != nil {
 			sess.Out.Error("Error saving session to %s: %s\n", *sess.Options.Report, err)
 		}
@@ -98,6 +148,46 @@ func main() {
 	}
 
 	sess.Stats.PrintStats(sess.Out)
+	if *sess.Options.TrackFindings {
+		newCount, existingCount, resolvedCount := sess.CountFindingStatuses()
+		sess.Out.Important("Findings: %d new, %d existing, %d resolved\n\n", newCount, existingCount, resolvedCount)
+	}
+
+	if *sess.Options.UI && ctx.Err() == nil {
+		sess.Out.Important("Press Ctrl+C to stop the UI server\n")
+		<-ctx.Done()
+	}
+	sess.Close()
+
+	if sess.FailOnFindings() {
+		sess.Out.Error("Found potential secrets of %s severity or above\n", *sess.Options.FailOn)
+		os.Exit(1)
+	}
+}
+
+// handleSignals returns a context which is cancelled on SIGINT or SIGTERM,
+// a second signal exits immediately
+func handleSignals(sess *session.Session) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		sess.Out.Important("Received %s, stopping scan and cleaning up...\n", sig)
+		cancel()
+		<-signals
+		os.Exit(1)
+	}()
+	return ctx
+}
+
+// serveUI serves the UI and REST API of the session
+func serveUI(sess *session.Session) {
+	sess.Out.Important("UI available at http://%s\n", net.JoinHostPort(*sess.Options.UIHost, *sess.Options.UIPort))
+	err := web.ListenAndServe(sess, *sess.Options.UIHost, *sess.Options.UIPort)
+	if err != nil {
+		sess.Out.Error("Unable to serve UI: %s\n", err)
+	}
 }
 
 func loadEnv(envPath string) {
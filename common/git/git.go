@@ -6,18 +6,30 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path"
+	"sort"
 	"strings"
 
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"gopkg.in/src-d/go-billy.v4/helper/mount"
+	"gopkg.in/src-d/go-billy.v4/helper/polyfill"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/osfs"
+
 	"gopkg.in/src-d/go-git.v4/plumbing/transport"
 
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/cache"
+	"gopkg.in/src-d/go-git.v4/plumbing/filemode"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/diff"
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/storage/filesystem"
+	textdiff "gopkg.in/src-d/go-git.v4/utils/diff"
 	"gopkg.in/src-d/go-git.v4/utils/merkletrie"
 )
 
@@ -27,7 +39,9 @@ const (
 )
 
 // CloneRepository clones a repository from a remote source to local temp. dir.
-func CloneRepository(url *string, branch *string, depth int, auth transport.AuthMethod) (*git.Repository, string, error) {
+// If allRefs is true, the full history of all branches and tags is fetched.
+// The temp. dir. is removed if the clone fails or is cancelled.
+func CloneRepository(ctx context.Context, url *string, branch *string, depth int, allRefs bool, auth transport.AuthMethod) (*git.Repository, string, error) {
 	urlVal := *url
 	branchVal := *branch
 	dir, err := ioutil.TempDir("", "secretscanner")
@@ -41,12 +55,18 @@ func CloneRepository(url *string, branch *string, depth int, auth transport.Auth
 		SingleBranch:  true,
 		Tags:          git.NoTags,
 	}
+	if allRefs {
+		cloneOpt.Depth = 0
+		cloneOpt.SingleBranch = false
+		cloneOpt.Tags = git.AllTags
+	}
 	if auth != nil {
 		cloneOpt.Auth = auth
 	}
-	repository, err := git.PlainClone(dir, false, cloneOpt)
+	repository, err := git.PlainCloneContext(ctx, dir, false, cloneOpt)
 	if err != nil {
-		return nil, dir, err
+		_ = os.RemoveAll(dir)
+		return nil, "", err
 	}
 	return repository, dir, nil
 }
@@ -69,14 +89,77 @@ func GetRepositoryHistory(repository *git.Repository) ([]*object.Commit, error)
 	return commits, nil
 }
 
+// GetAllRefsHistory gets the commits reachable from any branch or tag, each commit exactly once.
+// Commits reachable from the checkpoint are excluded.
+func GetAllRefsHistory(repository *git.Repository, checkpoint string) ([]*object.Commit, error) {
+	seen := map[plumbing.Hash]bool{}
+	if checkpoint != "" {
+		checkpointCommit, err := repository.CommitObject(plumbing.NewHash(checkpoint))
+		if err == nil {
+			err = object.NewCommitPreorderIter(checkpointCommit, nil, nil).ForEach(func(c *object.Commit) error {
+				seen[c.Hash] = true
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	refs, err := repository.References()
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*object.Commit
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		refCommit, err := peelToCommit(repository, ref.Hash())
+		if err != nil {
+			// skip refs not pointing to a commit
+			return nil
+		}
+		return object.NewCommitPreorderIter(refCommit, seen, nil).ForEach(func(c *object.Commit) error {
+			seen[c.Hash] = true
+			commits = append(commits, c)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return commits, nil
+}
+
+// peelToCommit resolves a commit or annotated tag hash to a commit
+func peelToCommit(repository *git.Repository, hash plumbing.Hash) (*object.Commit, error) {
+	commit, err := repository.CommitObject(hash)
+	if err == nil {
+		return commit, nil
+	}
+	tag, err := repository.TagObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	return tag.Commit()
+}
+
 // GetChanges gets the changes since a commit till current HEAD
 func GetChanges(commit *object.Commit, repo *git.Repository) (object.Changes, error) {
-	parentCommit, err := GetParentCommit(commit, repo)
+	commitTree, err := commit.Tree()
 	if err != nil {
 		return nil, err
 	}
 
-	commitTree, err := commit.Tree()
+	// the root commit is compared with an empty tree
+	if commit.NumParents() == 0 {
+		return object.DiffTree(nil, commitTree)
+	}
+
+	parentCommit, err := GetParentCommit(commit, repo)
 	if err != nil {
 		return nil, err
 	}
@@ -150,50 +233,306 @@ func GetPatch(change *object.Change) (*object.Patch, error) {
 	return patch, err
 }
 
-// GetLatestCommitHash runs a git cmd to return latest commit hash
-func GetLatestCommitHash(dir string) (string, error) {
-	os.Chdir(dir)
-	gitcmd := "git"
-	task := "rev-parse"
-	op1 := "--verify"
-	op2 := "HEAD"
-	out, err := exec.Command(gitcmd, task, op1, op2).CombinedOutput()
+// StagedFile contains the staged content of a file and its content at HEAD
+type StagedFile struct {
+	Path        string
+	Content     string
+	HeadContent string
+	IsNew       bool
+}
+
+// GetStagedFiles returns the files whose content in the index differs from HEAD.
+// Deleted files and submodules are not included.
+func GetStagedFiles(repository *git.Repository) ([]*StagedFile, error) {
+	idx, err := repository.Storer.Index()
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	var headTree *object.Tree
+	head, err := repository.Head()
+	if err != nil && err != plumbing.ErrReferenceNotFound {
+		return nil, err
 	}
-	commitHash := fmt.Sprintf("%s", strings.TrimSpace(string(out)))
-	return commitHash, nil
+	// a repository without commits has no HEAD, every staged file is new
+	if err == nil {
+		headCommit, err := repository.CommitObject(head.Hash())
+		if err != nil {
+			return nil, err
+		}
+		headTree, err = headCommit.Tree()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var stagedFiles []*StagedFile
+	for _, entry := range idx.Entries {
+		// unmerged entries have a non-zero stage, note that index.Merged is wrongly defined as 1 in go-git v4
+		if entry.Stage != 0 || entry.Mode == filemode.Submodule {
+			continue
+		}
+
+		stagedFile := &StagedFile{Path: entry.Name, IsNew: true}
+		if headTree != nil {
+			headFile, err := headTree.File(entry.Name)
+			if err == nil {
+				if headFile.Hash == entry.Hash {
+					continue
+				}
+				stagedFile.IsNew = false
+				stagedFile.HeadContent, err = headFile.Contents()
+				if err != nil {
+					return nil, err
+				}
+			} else if err != object.ErrFileNotFound {
+				return nil, err
+			}
+		}
+
+		blob, err := repository.BlobObject(entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+		reader, err := blob.Reader()
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			return nil, err
+		}
+		stagedFile.Content = string(content)
+
+		stagedFiles = append(stagedFiles, stagedFile)
+	}
+
+	return stagedFiles, nil
 }
 
-// GatherPaths gets all committed file paths
-func GatherPaths(dir, branch string, targets []string) ([]string, error) {
-	os.Chdir(dir)
-	gitcmd := "git"
-	listTree := "ls-tree"
-	op1 := "-r"
-	op2 := "--name-only"
-	var paths []string
+// GetAddedLines returns the line numbers (starting from 1) of lines in "to" that are not in "from"
+func GetAddedLines(from, to string) map[uint64]bool {
+	addedLines := map[uint64]bool{}
+	lineNo := uint64(1)
+	for _, d := range textdiff.Do(from, to) {
+		if d.Type == diffmatchpatch.DiffDelete {
+			continue
+		}
 
-	if len(targets) == 0 {
-		out, err := exec.Command(gitcmd, listTree, op1, branch, op2).CombinedOutput()
+		lineCount := countLines(d.Text)
+		if d.Type == diffmatchpatch.DiffInsert {
+			for i := uint64(0); i < lineCount; i++ {
+				addedLines[lineNo+i] = true
+			}
+		}
+		lineNo += lineCount
+	}
+	return addedLines
+}
+
+// GetPatchContent reconstructs the new file content from the patch of a change,
+// and returns the line numbers (starting from 1) of the lines added by the change
+func GetPatchContent(patch *object.Patch) (string, map[uint64]bool) {
+	var sb strings.Builder
+	addedLines := map[uint64]bool{}
+	lineNo := uint64(1)
+	for _, filePatch := range patch.FilePatches() {
+		for _, chunk := range filePatch.Chunks() {
+			if chunk.Type() == diff.Delete {
+				continue
+			}
+
+			lineCount := countLines(chunk.Content())
+			if chunk.Type() == diff.Add {
+				for i := uint64(0); i < lineCount; i++ {
+					addedLines[lineNo+i] = true
+				}
+			}
+			lineNo += lineCount
+			sb.WriteString(chunk.Content())
+		}
+	}
+	return sb.String(), addedLines
+}
+
+// countLines counts the lines of a text, the last line may not end with a newline
+func countLines(text string) uint64 {
+	lineCount := uint64(strings.Count(text, "\n"))
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		lineCount++
+	}
+	return lineCount
+}
+
+// OpenRepository opens a local repository, including bare repositories.
+// If a quarantine path is given, e.g. $GIT_QUARANTINE_PATH of a pre-receive hook,
+// objects not yet accepted into the repository are read from it.
+func OpenRepository(dir, quarantinePath string) (*git.Repository, error) {
+	if quarantinePath == "" {
+		return git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	}
+
+	gitDir := dir
+	if _, err := os.Stat(path.Join(dir, ".git")); err == nil {
+		gitDir = path.Join(dir, ".git")
+	}
+
+	// the quarantine directory has the layout of an objects directory
+	quarantineFS := polyfill.New(mount.New(memfs.New(), "objects", osfs.New(quarantinePath)))
+	storage := &quarantineStorage{
+		Storage:    filesystem.NewStorage(osfs.New(gitDir), cache.NewObjectLRUDefault()),
+		quarantine: filesystem.NewStorage(quarantineFS, cache.NewObjectLRUDefault()),
+	}
+	return git.Open(storage, nil)
+}
+
+// quarantineStorage looks up objects in the quarantine directory before the repository storage
+type quarantineStorage struct {
+	*filesystem.Storage
+	quarantine *filesystem.Storage
+}
+
+// EncodedObject gets an object from the quarantine or repository storage
+func (s *quarantineStorage) EncodedObject(t plumbing.ObjectType, h plumbing.Hash) (plumbing.EncodedObject, error) {
+	obj, err := s.quarantine.EncodedObject(t, h)
+	if err == plumbing.ErrObjectNotFound {
+		return s.Storage.EncodedObject(t, h)
+	}
+	return obj, err
+}
+
+// HasEncodedObject checks if an object exists in the quarantine or repository storage
+func (s *quarantineStorage) HasEncodedObject(h plumbing.Hash) error {
+	if err := s.quarantine.HasEncodedObject(h); err != plumbing.ErrObjectNotFound {
+		return err
+	}
+	return s.Storage.HasEncodedObject(h)
+}
+
+// EncodedObjectSize gets the size of an object from the quarantine or repository storage
+func (s *quarantineStorage) EncodedObjectSize(h plumbing.Hash) (int64, error) {
+	size, err := s.quarantine.EncodedObjectSize(h)
+	if err == plumbing.ErrObjectNotFound {
+		return s.Storage.EncodedObjectSize(h)
+	}
+	return size, err
+}
+
+// GetPushedCommits returns the commits reachable from the pushed hashes but not from any existing ref,
+// i.e. the commits introduced by a push. Each commit is returned once.
+func GetPushedCommits(repository *git.Repository, pushedHashes []plumbing.Hash) ([]*object.Commit, error) {
+	seen := map[plumbing.Hash]bool{}
+
+	refs, err := repository.References()
+	if err != nil {
+		return nil, err
+	}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		refCommit, err := peelToCommit(repository, ref.Hash())
+		if err != nil {
+			// skip refs not pointing to a commit
+			return nil
+		}
+		return object.NewCommitPreorderIter(refCommit, seen, nil).ForEach(func(c *object.Commit) error {
+			seen[c.Hash] = true
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*object.Commit
+	for _, hash := range pushedHashes {
+		pushedCommit, err := peelToCommit(repository, hash)
+		if err != nil {
+			// skip pushed refs not pointing to a commit
+			continue
+		}
+		err = object.NewCommitPreorderIter(pushedCommit, seen, nil).ForEach(func(c *object.Commit) error {
+			seen[c.Hash] = true
+			commits = append(commits, c)
+			return nil
+		})
 		if err != nil {
 			return nil, err
 		}
-		cmdout := fmt.Sprintf("%s", strings.TrimSpace(string(out)))
-		paths = append(paths, strings.Split(cmdout, "\n")...)
 	}
 
+	return commits, nil
+}
+
+// GetLatestCommitHash returns the commit hash of HEAD
+func GetLatestCommitHash(repository *git.Repository) (string, error) {
+	head, err := repository.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
+// GatherPaths gets all committed file paths of a branch, or of HEAD if branch is empty.
+// If targets are given, only files within the target sub-directories are returned.
+func GatherPaths(repository *git.Repository, branch string, targets []string) ([]string, error) {
+	blobHashes, err := GatherBlobHashes(repository, branch, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(blobHashes))
+	for p := range blobHashes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// GatherBlobHashes gets the blob hashes of all committed files of a branch, or of HEAD if branch is empty, by file path.
+// If targets are given, only files within the target sub-directories are returned.
+func GatherBlobHashes(repository *git.Repository, branch string, targets []string) (map[string]plumbing.Hash, error) {
+	revision := plumbing.Revision(plumbing.HEAD)
+	if branch != "" {
+		revision = plumbing.Revision(plumbing.NewBranchReferenceName(branch))
+	}
+	hash, err := repository.ResolveRevision(revision)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repository.CommitObject(*hash)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(targets) == 0 {
+		targets = []string{""}
+	}
+
+	blobHashes := map[string]plumbing.Hash{}
 	for _, t := range targets {
-		out, err := exec.Command(gitcmd, listTree, op1, fmt.Sprintf("%s:%s", branch, t), op2).CombinedOutput()
+		t = strings.Trim(t, "/")
+		targetTree := tree
+		if t != "" {
+			targetTree, err = tree.Tree(t)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", t, err)
+			}
+		}
+
+		err = targetTree.Files().ForEach(func(f *object.File) error {
+			blobHashes[path.Join(t, f.Name)] = f.Hash
+			return nil
+		})
 		if err != nil {
 			return nil, err
 		}
-		cmdout := fmt.Sprintf("%s", strings.TrimSpace(string(out)))
-		currentPaths := strings.Split(cmdout, "\n")
-		for i, p := range currentPaths {
-			currentPaths[i] = path.Join(t, p)
-		}
-		paths = append(paths, currentPaths...)
 	}
-	return paths, nil
+	return blobHashes, nil
 }
@@ -0,0 +1,280 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestGetAllRefsHistory(t *testing.T) {
+	dir, repo := createTestRepository(t)
+	defer cleanup(dir)
+
+	root := commitFile(t, dir, repo, "README.md", "# readme")
+	second := commitFile(t, dir, repo, "config.yml", "password: hunter2")
+
+	worktree, _ := repo.Worktree()
+	err := worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true})
+	if err != nil {
+		t.Fatalf("Cannot create branch: %v", err)
+	}
+	feature := commitFile(t, dir, repo, "feature.yml", "token: abc")
+	_, err = repo.CreateTag("v1.0.0", feature, &git.CreateTagOptions{
+		Tagger:  testSignature(),
+		Message: "release",
+	})
+	if err != nil {
+		t.Fatalf("Cannot create tag: %v", err)
+	}
+
+	commits, err := GetAllRefsHistory(repo, "")
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if len(commits) != 3 {
+		t.Errorf("Want 3, got %v", len(commits))
+	}
+
+	commits, err = GetAllRefsHistory(repo, second.String())
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if len(commits) != 1 || commits[0].Hash != feature {
+		t.Errorf("Want only commit %v, got %v commits", feature, len(commits))
+	}
+
+	rootCommit, _ := repo.CommitObject(root)
+	changes, err := GetChanges(rootCommit, repo)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if len(changes) != 1 || GetChangePath(changes[0]) != "README.md" {
+		t.Errorf("Want README.md change in root commit, got %v changes", len(changes))
+	}
+}
+
+func TestGetStagedFiles(t *testing.T) {
+	dir, repo := createTestRepository(t)
+	defer cleanup(dir)
+
+	stagedFiles, err := GetStagedFiles(repo)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if len(stagedFiles) != 0 {
+		t.Errorf("Want 0, got %v", len(stagedFiles))
+	}
+
+	commitFile(t, dir, repo, "README.md", "# readme\n")
+	commitFile(t, dir, repo, "config.yml", "user: admin\n")
+
+	stageFile(t, dir, repo, "config.yml", "user: admin\npassword: hunter2\n")
+	stageFile(t, dir, repo, ".env", "TOKEN=abc\n")
+
+	stagedFiles, err = GetStagedFiles(repo)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if len(stagedFiles) != 2 {
+		t.Errorf("Want 2, got %v", len(stagedFiles))
+		return
+	}
+	for _, stagedFile := range stagedFiles {
+		switch stagedFile.Path {
+		case ".env":
+			if !stagedFile.IsNew || stagedFile.HeadContent != "" {
+				t.Errorf("Want new file without HEAD content, got %+v", stagedFile)
+			}
+		case "config.yml":
+			if stagedFile.IsNew || stagedFile.HeadContent != "user: admin\n" || stagedFile.Content != "user: admin\npassword: hunter2\n" {
+				t.Errorf("Want modified file with HEAD content, got %+v", stagedFile)
+			}
+		default:
+			t.Errorf("Want only staged files, got %v", stagedFile.Path)
+		}
+	}
+}
+
+func TestGetAddedLines(t *testing.T) {
+	addedLines := GetAddedLines("a\nb\nc\n", "a\nx\nb\nc\ny")
+	if len(addedLines) != 2 || !addedLines[2] || !addedLines[5] {
+		t.Errorf("Want lines 2 and 5, got %v", addedLines)
+	}
+
+	addedLines = GetAddedLines("", "a\nb\n")
+	if len(addedLines) != 2 || !addedLines[1] || !addedLines[2] {
+		t.Errorf("Want lines 1 and 2, got %v", addedLines)
+	}
+
+	addedLines = GetAddedLines("a\nb\n", "b\n")
+	if len(addedLines) != 0 {
+		t.Errorf("Want no lines, got %v", addedLines)
+	}
+}
+
+func TestGetPushedCommits(t *testing.T) {
+	dir, repo := createTestRepository(t)
+	defer cleanup(dir)
+
+	commitFile(t, dir, repo, "README.md", "# readme\n")
+	second := commitFile(t, dir, repo, "config.yml", "user: admin\n")
+	third := commitFile(t, dir, repo, "config.yml", "user: admin\npassword: hunter2\n")
+
+	// pretend master is still at the second commit
+	err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.Master, second))
+	if err != nil {
+		t.Fatalf("Cannot set reference: %v", err)
+	}
+
+	commits, err := GetPushedCommits(repo, []plumbing.Hash{third, third})
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if len(commits) != 1 || commits[0].Hash != third {
+		t.Errorf("Want only commit %v, got %v commits", third, len(commits))
+		return
+	}
+
+	changes, err := GetChanges(commits[0], repo)
+	if err != nil || len(changes) != 1 {
+		t.Errorf("Want 1 change, got %v changes, err: %v", len(changes), err)
+		return
+	}
+	patch, err := GetPatch(changes[0])
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	content, addedLines := GetPatchContent(patch)
+	if content != "user: admin\npassword: hunter2\n" {
+		t.Errorf("Want new file content, got %q", content)
+	}
+	if len(addedLines) != 1 || !addedLines[2] {
+		t.Errorf("Want line 2, got %v", addedLines)
+	}
+}
+
+func TestGatherPaths(t *testing.T) {
+	dir, repo := createTestRepository(t)
+	defer cleanup(dir)
+
+	err := os.MkdirAll(path.Join(dir, "src", "config"), 0700)
+	if err != nil {
+		t.Fatalf("Cannot create dir: %v", err)
+	}
+	commitFile(t, dir, repo, "README.md", "# readme")
+	commitFile(t, dir, repo, "src/main.go", "package main")
+	head := commitFile(t, dir, repo, "src/config/app.yml", "password: hunter2")
+
+	paths, err := GatherPaths(repo, "", nil)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if len(paths) != 3 {
+		t.Errorf("Want 3, got %v", len(paths))
+	}
+
+	paths, err = GatherPaths(repo, "master", []string{"src/config/", "README.md"})
+	if err == nil {
+		t.Errorf("Want err for file target, got paths %v", paths)
+	}
+
+	paths, err = GatherPaths(repo, "master", []string{"src/config/"})
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if len(paths) != 1 || paths[0] != "src/config/app.yml" {
+		t.Errorf("Want src/config/app.yml, got %v", paths)
+	}
+
+	blobHashes, err := GatherBlobHashes(repo, "", []string{"src/config/"})
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	want := plumbing.ComputeHash(plumbing.BlobObject, []byte("password: hunter2"))
+	if len(blobHashes) != 1 || blobHashes["src/config/app.yml"] != want {
+		t.Errorf("Want %v, got %v", want, blobHashes)
+	}
+
+	_, err = GatherPaths(repo, "no-such-branch", nil)
+	if err == nil {
+		t.Errorf("Want err, got no err")
+	}
+
+	hash, err := GetLatestCommitHash(repo)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if hash != head.String() {
+		t.Errorf("Want %v, got %v", head, hash)
+	}
+}
+
+func createTestRepository(t *testing.T) (string, *git.Repository) {
+	dir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Fatalf("Cannot create temp. dir.: %v", err)
+	}
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("Cannot init repository: %v", err)
+	}
+	return dir, repo
+}
+
+func commitFile(t *testing.T, dir string, repo *git.Repository, filename, content string) plumbing.Hash {
+	stageFile(t, dir, repo, filename, content)
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Cannot get worktree: %v", err)
+	}
+	hash, err := worktree.Commit("add "+filename, &git.CommitOptions{Author: testSignature()})
+	if err != nil {
+		t.Fatalf("Cannot commit: %v", err)
+	}
+	return hash
+}
+
+func stageFile(t *testing.T, dir string, repo *git.Repository, filename, content string) {
+	err := ioutil.WriteFile(path.Join(dir, filename), []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("Cannot write file: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Cannot get worktree: %v", err)
+	}
+	_, err = worktree.Add(filename)
+	if err != nil {
+		t.Fatalf("Cannot add file: %v", err)
+	}
+}
+
+func testSignature() *object.Signature {
+	return &object.Signature{Name: "Jane Doe", Email: "jane@example.com", When: time.Now()}
+}
+
+func cleanup(path string) {
+	_ = os.RemoveAll(path)
+}
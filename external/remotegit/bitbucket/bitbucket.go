@@ -6,6 +6,7 @@
 package bitbucket
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -35,35 +36,59 @@ type Bitbucket struct {
 }
 
 // UserRepository fetches a user's repository
-func (bb *Bitbucket) UserRepository(userSlug, repoSlug string) (*Repository, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s", bb.config.BaseURL, path.Join("repositories", userSlug, repoSlug)), nil)
+func (bb *Bitbucket) UserRepository(ctx context.Context, userSlug, repoSlug string) (*Repository, error) {
+	repo := &Repository{}
+	err := bb.get(ctx, fmt.Sprintf("%s/%s", bb.config.BaseURL, path.Join("repositories", userSlug, repoSlug)), repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// WorkspaceRepositories fetches a page of a workspace's repositories,
+// the first page is fetched if pageURL is empty
+func (bb *Bitbucket) WorkspaceRepositories(ctx context.Context, workspace, pageURL string, pageLen int) (*RepositoryPage, error) {
+	if pageURL == "" {
+		pageURL = fmt.Sprintf("%s/%s?pagelen=%d", bb.config.BaseURL, path.Join("repositories", workspace), pageLen)
+	}
+
+	page := &RepositoryPage{}
+	err := bb.get(ctx, pageURL, page)
 	if err != nil {
 		return nil, err
 	}
+
+	return page, nil
+}
+
+func (bb *Bitbucket) get(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
 	if bb.token != nil {
 		req.Header.Set("Authorization", fmt.Sprintf("%s %s", "Bearer ", bb.token.AccessToken))
 	}
 
 	resp, err := bb.Client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
 	if resp.StatusCode != http.StatusOK {
-		return nil, ErrResponseNotOK
+		return ErrResponseNotOK
 	}
 
 	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
-	}
-
-	repo := &Repository{}
-	err = json.Unmarshal(respBody, repo)
-	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return repo, nil
+	return json.Unmarshal(respBody, v)
 }
 
 // NewClient generates a new Bitbucket service client
@@ -37,6 +37,15 @@ type Repository struct {
 	Description string           `json:"description"`
 }
 
+// RepositoryPage fields
+type RepositoryPage struct {
+	Size    int           `json:"size"`
+	Page    int           `json:"page"`
+	PageLen int           `json:"pagelen"`
+	Next    string        `json:"next"`
+	Values  []*Repository `json:"values"`
+}
+
 // RepositoryLinks fields
 type RepositoryLinks struct {
 	Watchers     *Link   `json:"watchers"`
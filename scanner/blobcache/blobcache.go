@@ -0,0 +1,83 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package blobcache
+
+import (
+	"sort"
+	"sync"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// Cache remembers the hashes of content without any content signature match.
+// Entries are only valid for the signature set version the cache was created with.
+type Cache struct {
+	mutex   sync.RWMutex
+	version string
+	clean   map[string]bool
+}
+
+// New creates an empty cache for a signature set version
+func New(version string) *Cache {
+	return &Cache{
+		version: version,
+		clean:   map[string]bool{},
+	}
+}
+
+// Hash returns the git blob hash of content
+func Hash(content string) string {
+	return plumbing.ComputeHash(plumbing.BlobObject, []byte(content)).String()
+}
+
+// Version returns the signature set version of the cache
+func (c *Cache) Version() string {
+	if c == nil {
+		return ""
+	}
+	return c.version
+}
+
+// IsClean checks if content with the given hash is known to have no content signature match
+func (c *Cache) IsClean(hash string) bool {
+	if c == nil {
+		return false
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.clean[hash]
+}
+
+// MarkClean records that content with the given hash has no content signature match
+func (c *Cache) MarkClean(hash string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.clean[hash] = true
+}
+
+// Load adds previously persisted hashes
+func (c *Cache) Load(hashes []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, hash := range hashes {
+		c.clean[hash] = true
+	}
+}
+
+// Hashes returns all cached hashes in sorted order
+func (c *Cache) Hashes() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	hashes := make([]string, 0, len(c.clean))
+	for hash := range c.clean {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	return hashes
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package blobcache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	// same as `echo -n "hello world" | git hash-object --stdin`
+	want := "95d09f2b10159347eece71399a7e2e907ea3df4f"
+	if got := Hash("hello world"); got != want {
+		t.Errorf("Want %v, got %v", want, got)
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := New("v1")
+	if c.Version() != "v1" {
+		t.Errorf("Want v1, got %v", c.Version())
+	}
+
+	hash := Hash("clean content")
+	if c.IsClean(hash) {
+		t.Errorf("Want not clean, got clean")
+	}
+	c.MarkClean(hash)
+	if !c.IsClean(hash) {
+		t.Errorf("Want clean, got not clean")
+	}
+
+	c.Load([]string{"b", "a"})
+	want := []string{"a", "b", hash}
+	if got := c.Hashes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Want %v, got %v", want, got)
+	}
+}
+
+func TestCache_Nil(t *testing.T) {
+	var c *Cache
+	c.MarkClean("a")
+	if c.IsClean("a") {
+		t.Errorf("Want not clean, got clean")
+	}
+	if c.Version() != "" {
+		t.Errorf("Want empty version, got %v", c.Version())
+	}
+}
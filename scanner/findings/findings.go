@@ -6,6 +6,7 @@
 package findings
 
 import (
+	"crypto/hmac"
 	"crypto/sha256"
 	"fmt"
 	"io"
@@ -14,33 +15,68 @@ import (
 const (
 	// MaxLineChar defines the maximum number of characters in line content
 	MaxLineChar = 100
+
+	// MaxContextLineChar defines the maximum number of characters in a line of the context
+	MaxContextLineChar = 200
+
+	// ContextRadius defines the number of lines before and after a match kept as context
+	ContextRadius = 3
+
+	// SecretVisibleChars defines the number of characters shown at both ends of a masked secret
+	SecretVisibleChars = 4
+
+	// SecretMask replaces the hidden characters of a masked secret
+	SecretMask = "****"
 )
 
 // Finding holds the info for scan finding
 type Finding struct {
-	ID              string
-	FilePath        string
-	Action          string
-	Description     string
-	Comment         string
-	RepositoryOwner string
-	RepositoryName  string
-	CommitHash      string
-	CommitMessage   string
-	CommitAuthor    string
-	FileURL         string
-	Line            uint64
-	LineContent     string
-	CommitURL       string
-	RepositoryURL   string
-	IsTestContext   bool
-}
-
-// GenerateHashID generates an unique hash
+	ID                string
+	FilePath          string
+	Action            string
+	Description       string
+	SignatureID       string
+	Comment           string
+	Severity          string
+	Confidence        string
+	Tags              []string
+	RepositoryOwner   string
+	RepositoryName    string
+	CommitHash        string
+	CommitMessage     string
+	CommitAuthor      string
+	CommitAuthorEmail string
+	CommitAuthorDate  string
+	CommitDate        string
+	FileURL           string
+	Line              uint64
+	Column            uint64
+	LineContent       string
+	ContextStartLine  uint64
+	ContextLines      []string
+	Secret            string
+	SecretMasked      string
+	SecretHash        string
+	Verified          string
+	CommitURL         string
+	RepositoryURL     string
+	IsTestContext     bool
+	IgnoreReason      string
+	Fingerprint       string
+	Status            string
+	FirstSeen         string
+}
+
+// GenerateHashID generates an unique hash of the file, rule and secret hash of the finding.
+// It does not depend on -log-secret, and differs for different secrets on the same line.
 func (f *Finding) GenerateHashID() (hash string, err error) {
-	// Used for dedupe in defect dojo
+	// Used for dedupe in defect dojo, and by fingerprint ignore rules
 	h := sha256.New()
-	str := fmt.Sprintf("%s%s%v%s", f.FileURL, f.Action, f.Line, f.LineContent)
+	signature := f.SignatureID
+	if signature == "" {
+		signature = f.Description
+	}
+	str := fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%s", f.FileURL, f.FilePath, f.Action, signature, f.SecretHash)
 
 	_, err = io.WriteString(h, str)
 	if err != nil {
@@ -54,6 +90,17 @@ func (f *Finding) GenerateHashID() (hash string, err error) {
 	// io.WriteString(h, f.CommitAuthor)
 }
 
+// TrackingFingerprint returns the fingerprint identifying a finding across scans of a repository.
+// Unlike the ID, it does not change if lines are added above the secret,
+// and differs for different secrets on the same line.
+func (f *Finding) TrackingFingerprint(repoID string) string {
+	signature := f.SignatureID
+	if signature == "" {
+		signature = f.Description
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(repoID+"\x00"+f.FilePath+"\x00"+signature+"\x00"+f.SecretHash)))
+}
+
 // TruncateLineContent truncates line content
 func (f *Finding) TruncateLineContent(maxLen int) {
 	lineLen := len(f.LineContent)
@@ -61,3 +108,42 @@ func (f *Finding) TruncateLineContent(maxLen int) {
 		f.LineContent = f.LineContent[0:maxLen]
 	}
 }
+
+// SetContext sets the lines surrounding the match, long lines are truncated
+func (f *Finding) SetContext(startLine uint64, lines []string) {
+	f.ContextStartLine = startLine
+	f.ContextLines = make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) > MaxContextLineChar {
+			line = line[:MaxContextLineChar]
+		}
+		f.ContextLines[i] = line
+	}
+}
+
+// SetSecret sets the masked form and the salted hash of the secret
+func (f *Finding) SetSecret(secret, salt string) {
+	if secret == "" {
+		return
+	}
+	f.SecretMasked = MaskSecret(secret)
+	f.SecretHash = HashSecret(secret, salt)
+}
+
+// MaskSecret shows the first and last characters of a secret only,
+// secrets too short to hide at least as many characters as shown are fully masked
+func MaskSecret(secret string) string {
+	runes := []rune(secret)
+	if len(runes) < 3*SecretVisibleChars {
+		return SecretMask
+	}
+	return string(runes[:SecretVisibleChars]) + SecretMask + string(runes[len(runes)-SecretVisibleChars:])
+}
+
+// HashSecret returns the HMAC-SHA256 of a secret keyed with the salt,
+// findings of the same secret have the same hash if scanned with the same salt
+func HashSecret(secret, salt string) string {
+	h := hmac.New(sha256.New, []byte(salt))
+	_, _ = io.WriteString(h, secret)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
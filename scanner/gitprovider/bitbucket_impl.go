@@ -6,6 +6,7 @@
 package gitprovider
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -60,7 +61,7 @@ func (g *BitbucketProvider) Initialize(baseURL, token string, additionalParams m
 }
 
 // GetRepository gets repo info
-func (g *BitbucketProvider) GetRepository(opt map[string]string) (*Repository, error) {
+func (g *BitbucketProvider) GetRepository(ctx context.Context, opt map[string]string) (*Repository, error) {
 	username, exists := opt["owner"]
 	if !exists {
 		return nil, errors.New("username option must exist in map")
@@ -71,11 +72,43 @@ func (g *BitbucketProvider) GetRepository(opt map[string]string) (*Repository, e
 		return nil, errors.New("repoSlug option must exist in map")
 	}
 
-	repo, err := g.Client.UserRepository(username, repoSlug)
+	repo, err := g.Client.UserRepository(ctx, username, repoSlug)
 	if err != nil {
 		return nil, err
 	}
 
+	return newBitbucketRepository(repo), nil
+}
+
+// ListRepositories lists all repositories of a workspace
+func (g *BitbucketProvider) ListRepositories(ctx context.Context, opt map[string]string) ([]*Repository, error) {
+	workspace, exists := opt["owner"]
+	if !exists {
+		return nil, errors.New("workspace option must exist in map")
+	}
+
+	var repos []*Repository
+	pageURL := ""
+	for {
+		page, err := g.Client.WorkspaceRepositories(ctx, workspace, pageURL, ListPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, repo := range page.Values {
+			repos = append(repos, newBitbucketRepository(repo))
+		}
+
+		if page.Next == "" {
+			break
+		}
+		pageURL = page.Next
+	}
+
+	return repos, nil
+}
+
+func newBitbucketRepository(repo *bitbucket.Repository) *Repository {
 	return &Repository{
 		Owner:         repo.Owner.Username,
 		ID:            repo.UUID,
@@ -86,7 +119,7 @@ func (g *BitbucketProvider) GetRepository(opt map[string]string) (*Repository, e
 		DefaultBranch: repo.MainBranch.Name,
 		Description:   repo.Description,
 		Homepage:      repo.Links.HTML.Href,
-	}, nil
+	}
 }
 
 // GetAdditionalParams validates additional params
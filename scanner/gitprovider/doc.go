@@ -36,6 +36,11 @@ const (
 	BitbucketParamPassword = "BITBUCKET_PASSWORD"
 )
 
+const (
+	// ListPageSize is the number of repositories requested per page when listing repositories
+	ListPageSize = 100
+)
+
 var (
 	// ErrInvalidAdditionalParams ...
 	ErrInvalidAdditionalParams = errors.New("invalid additional params")
@@ -55,7 +55,7 @@ func (g *GithubProvider) Initialize(baseURL, token string, additionalParams map[
 }
 
 // GetRepository gets repo info
-func (g *GithubProvider) GetRepository(opt map[string]string) (*Repository, error) {
+func (g *GithubProvider) GetRepository(ctx context.Context, opt map[string]string) (*Repository, error) {
 	owner, exists := opt["owner"]
 	if !exists {
 		return nil, errors.New("owner option must exist in map")
@@ -66,11 +66,47 @@ func (g *GithubProvider) GetRepository(opt map[string]string) (*Repository, erro
 		return nil, errors.New("repo option must exist in map")
 	}
 
-	r, _, err := g.Client.Repositories.Get(context.Background(), owner, repo)
+	r, _, err := g.Client.Repositories.Get(ctx, owner, repo)
 	if err != nil {
 		return nil, err
 	}
 
+	return newGithubRepository(r), nil
+}
+
+// ListRepositories lists all repositories of an organization or user
+func (g *GithubProvider) ListRepositories(ctx context.Context, opt map[string]string) ([]*Repository, error) {
+	owner, exists := opt["owner"]
+	if !exists {
+		return nil, errors.New("owner option must exist in map")
+	}
+
+	var repos []*Repository
+	listOpt := github.ListOptions{PerPage: ListPageSize}
+	for {
+		rs, resp, err := g.Client.Repositories.ListByOrg(ctx, owner, &github.RepositoryListByOrgOptions{ListOptions: listOpt})
+		if err != nil && resp != nil && resp.StatusCode == http.StatusNotFound {
+			// owner is not an organization, fall back to user repositories
+			rs, resp, err = g.Client.Repositories.List(ctx, owner, &github.RepositoryListOptions{ListOptions: listOpt})
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range rs {
+			repos = append(repos, newGithubRepository(r))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		listOpt.Page = resp.NextPage
+	}
+
+	return repos, nil
+}
+
+func newGithubRepository(r *github.Repository) *Repository {
 	return &Repository{
 		ID:            strconv.Itoa(int(r.GetID())),
 		Name:          r.GetName(),
@@ -81,7 +117,7 @@ func (g *GithubProvider) GetRepository(opt map[string]string) (*Repository, erro
 		Description:   r.GetDescription(),
 		Homepage:      r.GetHomepage(),
 		Owner:         r.GetOwner().GetName(),
-	}, nil
+	}
 }
 
 // GetAdditionalParams validates additional params
@@ -6,6 +6,7 @@
 package gitprovider
 
 import (
+	"context"
 	"testing"
 )
 
@@ -30,7 +31,7 @@ func TestGithubProvider_GetRepository(t *testing.T) {
 		return
 	}
 
-	_, err = provider.GetRepository(opt)
+	_, err = provider.GetRepository(context.Background(), opt)
 	if err == nil {
 		t.Errorf("Want err, got no err")
 		return
@@ -38,7 +39,7 @@ func TestGithubProvider_GetRepository(t *testing.T) {
 
 	opt["owner"] = "my-owner"
 	opt["repo"] = "repo"
-	repo, err := provider.GetRepository(opt)
+	repo, err := provider.GetRepository(context.Background(), opt)
 	if err != nil {
 		t.Errorf("Want no err, got err: %v", err)
 		return
@@ -49,6 +50,43 @@ func TestGithubProvider_GetRepository(t *testing.T) {
 	}
 }
 
+func TestGithubProvider_ListRepositories(t *testing.T) {
+	provider := createNewGithubProvider()
+	opt := map[string]string{}
+	err := provider.Initialize(server.URL+"/github/", "my-token", nil)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+
+	_, err = provider.ListRepositories(context.Background(), opt)
+	if err == nil {
+		t.Errorf("Want err, got no err")
+		return
+	}
+
+	opt["owner"] = "my-org"
+	repos, err := provider.ListRepositories(context.Background(), opt)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if len(repos) != 2 {
+		t.Errorf("Want 2, got %v", len(repos))
+		return
+	}
+	if repos[0].Name != "repo-1" {
+		t.Errorf("Want repo-1, got %v", repos[0].Name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = provider.ListRepositories(ctx, opt)
+	if err == nil {
+		t.Errorf("Want err, got no err")
+	}
+}
+
 func TestGithubProvider_ValidateAdditionalParams(t *testing.T) {
 	provider := createNewGithubProvider()
 	if !provider.ValidateAdditionalParams(map[string]string{}) {
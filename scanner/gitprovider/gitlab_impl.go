@@ -6,6 +6,7 @@
 package gitprovider
 
 import (
+	"context"
 	"errors"
 	"strconv"
 
@@ -40,17 +41,53 @@ func (g *GitlabProvider) Initialize(baseURL, token string, additionalParams map[
 }
 
 // GetRepository gets repo info
-func (g *GitlabProvider) GetRepository(opt map[string]string) (*Repository, error) {
+func (g *GitlabProvider) GetRepository(ctx context.Context, opt map[string]string) (*Repository, error) {
 	id, exists := opt["id"]
 	if !exists {
 		return nil, errors.New("id option does not exists in map")
 	}
-	proj, _, err := g.Client.Projects.GetProject(id, nil)
+	proj, _, err := g.Client.Projects.GetProject(id, nil, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
 
-	repo := &Repository{
+	return newGitlabRepository(proj), nil
+}
+
+// ListRepositories lists all projects of a group, including projects of its subgroups
+func (g *GitlabProvider) ListRepositories(ctx context.Context, opt map[string]string) ([]*Repository, error) {
+	group, exists := opt["group"]
+	if !exists {
+		return nil, errors.New("group option does not exists in map")
+	}
+
+	var repos []*Repository
+	includeSubgroups := true
+	listOpt := &gitlab.ListGroupProjectsOptions{
+		ListOptions:      gitlab.ListOptions{PerPage: ListPageSize, Page: 1},
+		IncludeSubgroups: &includeSubgroups,
+	}
+	for {
+		projs, resp, err := g.Client.Groups.ListGroupProjects(group, listOpt, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, proj := range projs {
+			repos = append(repos, newGitlabRepository(proj))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		listOpt.Page = resp.NextPage
+	}
+
+	return repos, nil
+}
+
+func newGitlabRepository(proj *gitlab.Project) *Repository {
+	return &Repository{
 		ID:            strconv.Itoa(proj.ID),
 		Name:          proj.Name,
 		FullName:      proj.Name,
@@ -61,7 +98,6 @@ func (g *GitlabProvider) GetRepository(opt map[string]string) (*Repository, erro
 		Homepage:      proj.WebURL,
 		Owner:         "",
 	}
-	return repo, nil
 }
 
 // GetAdditionalParams validates additional params
@@ -6,6 +6,7 @@
 package gitprovider
 
 import (
+	"context"
 	"testing"
 )
 
@@ -30,14 +31,14 @@ func TestGitlabProvider_GetRepository(t *testing.T) {
 		return
 	}
 
-	_, err = provider.GetRepository(opt)
+	_, err = provider.GetRepository(context.Background(), opt)
 	if err == nil {
 		t.Errorf("Want err, got no err")
 		return
 	}
 
 	opt["id"] = "7824084"
-	repo, err := provider.GetRepository(opt)
+	repo, err := provider.GetRepository(context.Background(), opt)
 	if err != nil {
 		t.Errorf("Want no err, got err: %v", err)
 		return
@@ -48,6 +49,43 @@ func TestGitlabProvider_GetRepository(t *testing.T) {
 	}
 }
 
+func TestGitlabProvider_ListRepositories(t *testing.T) {
+	provider := createNewGitlabProvider()
+	opt := map[string]string{}
+	err := provider.Initialize(server.URL+"/gitlab", "my-token", nil)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+
+	_, err = provider.ListRepositories(context.Background(), opt)
+	if err == nil {
+		t.Errorf("Want err, got no err")
+		return
+	}
+
+	opt["group"] = "my-group"
+	repos, err := provider.ListRepositories(context.Background(), opt)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if len(repos) != 2 {
+		t.Errorf("Want 2, got %v", len(repos))
+		return
+	}
+	if repos[0].Name != "project-1" {
+		t.Errorf("Want project-1, got %v", repos[0].Name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = provider.ListRepositories(ctx, opt)
+	if err == nil {
+		t.Errorf("Want err, got no err")
+	}
+}
+
 func TestGitlabProvider_ValidateAdditionalParams(t *testing.T) {
 	provider := createNewGitlabProvider()
 	if !provider.ValidateAdditionalParams(map[string]string{}) {
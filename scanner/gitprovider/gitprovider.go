@@ -5,11 +5,14 @@
 
 package gitprovider
 
+import "context"
+
 // GitProvider defines interface for interacting with remote Git services
 type GitProvider interface {
 	Initialize(baseURL, token string, additionalParams map[string]string) error
 	GetAdditionalParam(key string) string
 	ValidateAdditionalParams(additionalParams map[string]string) bool
-	GetRepository(opt map[string]string) (*Repository, error)
+	GetRepository(ctx context.Context, opt map[string]string) (*Repository, error)
+	ListRepositories(ctx context.Context, opt map[string]string) ([]*Repository, error)
 	Name() string
 }
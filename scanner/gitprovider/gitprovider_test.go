@@ -6,6 +6,7 @@
 package gitprovider
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -23,8 +24,8 @@ func TestMain(m *testing.M) {
 }
 
 func setupServer() *httptest.Server {
-	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		rw.WriteHeader(200)
+	var s *httptest.Server
+	s = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		path := strings.Trim(req.URL.Path, "/")
 		pathParts := strings.Split(path, "/")
 
@@ -33,6 +34,30 @@ func setupServer() *httptest.Server {
 			return
 		}
 
+		nextPage := req.URL.Query().Get("page") != "2"
+
+		switch {
+		case strings.HasPrefix(path, "github/orgs/"):
+			if nextPage {
+				rw.Header().Set("Link", fmt.Sprintf(`<%s/%s?page=2>; rel="next"`, s.URL, path))
+			}
+			_, _ = rw.Write([]byte(fmt.Sprintf(`[{"id":%d,"name":"repo-%d","full_name":"my-org/repo-%d","default_branch":"master"}]`, pageNum(nextPage), pageNum(nextPage), pageNum(nextPage))))
+			return
+		case strings.HasPrefix(path, "gitlab/api/v4/groups/"):
+			if nextPage {
+				rw.Header().Set("X-Next-Page", "2")
+			}
+			_, _ = rw.Write([]byte(fmt.Sprintf(`[{"id":%d,"name":"project-%d","default_branch":"master"}]`, pageNum(nextPage), pageNum(nextPage))))
+			return
+		case strings.HasPrefix(path, "bitbucket/repositories/") && len(pathParts) == 3:
+			next := ""
+			if nextPage {
+				next = fmt.Sprintf("%s/%s?page=2", s.URL, path)
+			}
+			_, _ = rw.Write([]byte(fmt.Sprintf(`{"pagelen":1,"next":"%s","values":[{"uuid":"{%d}","name":"repo-%d","full_name":"my-workspace/repo-%d","owner":{"username":"my-workspace"},"mainbranch":{"name":"master"},"links":{"clone":[{"href":"https://bitbucket.org/my-workspace/repo.git"}],"self":{"href":""},"html":{"href":""}}}]}`, next, pageNum(nextPage), pageNum(nextPage), pageNum(nextPage))))
+			return
+		}
+
 		switch pathParts[0] {
 		case "github":
 			// https://api.github.com/repos/jquery/jquery
@@ -47,6 +72,14 @@ func setupServer() *httptest.Server {
 			_, _ = rw.Write([]byte(``))
 		}
 	}))
+	return s
+}
+
+func pageNum(nextPage bool) int {
+	if nextPage {
+		return 1
+	}
+	return 2
 }
 
 func teardownServer(s *httptest.Server) {
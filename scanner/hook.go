@@ -0,0 +1,319 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/utils/merkletrie"
+
+	gitHandler "github.com/grab/secret-scanner/common/git"
+	"github.com/grab/secret-scanner/scanner/findings"
+	"github.com/grab/secret-scanner/scanner/ignore"
+	"github.com/grab/secret-scanner/scanner/session"
+	"github.com/grab/secret-scanner/scanner/signatures"
+	"github.com/grab/secret-scanner/scanner/verify"
+)
+
+// ScanStagedChanges scans the changes staged in the index of a local repository against HEAD.
+// Content matches are only reported on lines added by the staged changes,
+// file name and path matches only for new files.
+func ScanStagedChanges(sess *session.Session, dir string) error {
+	repository, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return err
+	}
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return err
+	}
+	root := worktree.Filesystem.Root()
+	repoName := filepath.Base(root)
+
+	ignoreList, err := loadRepoIgnoreList(sess, root)
+	if err != nil {
+		sess.Out.Error("Unable to load %s ignore file: %s\n", repoName, err)
+	}
+
+	stagedFiles, err := gitHandler.GetStagedFiles(repository)
+	if err != nil {
+		return err
+	}
+	sess.Out.Debug("[HOOK][%s] Staged files: %d\n", repoName, len(stagedFiles))
+
+	for _, stagedFile := range stagedFiles {
+		matchFile := signatures.NewMatchFile(stagedFile.Path, stagedFile.Content)
+		if matchFile.IsSkippable() {
+			sess.Out.Debug("[HOOK][%s] Skipping %s\n", repoName, matchFile.Path)
+			continue
+		}
+		isTestContext := matchFile.IsTestContext()
+		if isTestContext && *sess.Options.SkipTestContexts {
+			sess.Out.Debug("[HOOK][%s] Skipping %s\n", repoName, matchFile.Path)
+			continue
+		}
+
+		addedLines := gitHandler.GetAddedLines(stagedFile.HeadContent, stagedFile.Content)
+		for _, signature := range sess.Prefilter.Select(matchFile) {
+			isContentSignature := signature.Part() == signatures.PartContent
+			// file name and path matches have been reported when the file was added
+			if !isContentSignature && !stagedFile.IsNew {
+				continue
+			}
+
+			for _, match := range signature.Match(matchFile) {
+				if isContentSignature && !addedLines[match.Line] {
+					continue
+				}
+
+				finding := &findings.Finding{
+					FilePath:       stagedFile.Path,
+					Action:         signature.Part(),
+					Description:    signature.Description(),
+					SignatureID:    signature.ID(),
+					Comment:        signature.Comment(),
+					Severity:       signature.Severity(),
+					Confidence:     signature.Confidence(),
+					Tags:           signature.Tags(),
+					RepositoryName: repoName,
+					Line:           match.Line,
+					Column:         match.Column,
+					IsTestContext:  isTestContext,
+				}
+
+				setFindingSecret(sess, finding, match, matchFile)
+
+				if admitFinding(sess, repoName, finding, match, ignoreList) {
+					verifyFinding(context.Background(), sess, repoName, finding, match, matchFile)
+					sess.AddFinding(finding)
+					sess.Stats.IncrementFindings()
+				}
+			}
+		}
+		sess.Stats.IncrementFiles()
+	}
+
+	return nil
+}
+
+// RefUpdate is a ref update line of the pre-receive hook input
+type RefUpdate struct {
+	OldHash plumbing.Hash
+	NewHash plumbing.Hash
+	Ref     string
+}
+
+// ParseRefUpdates parses "<old-value> <new-value> <ref-name>" lines as given to a pre-receive hook
+func ParseRefUpdates(r io.Reader) ([]*RefUpdate, error) {
+	var updates []*RefUpdate
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid ref update: %s", line)
+		}
+		updates = append(updates, &RefUpdate{
+			OldHash: plumbing.NewHash(fields[0]),
+			NewHash: plumbing.NewHash(fields[1]),
+			Ref:     fields[2],
+		})
+	}
+	return updates, scanner.Err()
+}
+
+// ScanPushedCommits scans the commits introduced by a push in a pre-receive hook.
+// Objects of the push are read from $GIT_QUARANTINE_PATH if set.
+// Content matches are only reported on lines added by a commit, file name and path matches only for new files.
+func ScanPushedCommits(sess *session.Session, dir string, updates []*RefUpdate) error {
+	repository, err := gitHandler.OpenRepository(dir, os.Getenv("GIT_QUARANTINE_PATH"))
+	if err != nil {
+		return err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	repoName := strings.TrimSuffix(filepath.Base(strings.TrimSuffix(absDir, "/.git")), ".git")
+
+	// ignore files are read from the ref tips before the push, so that a push cannot whitelist its own secrets
+	ignoreList := sess.IgnoreList
+	var pushedHashes []plumbing.Hash
+	ignoreHashes := map[plumbing.Hash]bool{}
+	for _, update := range updates {
+		// deleted refs introduce no commits
+		if update.NewHash == plumbing.ZeroHash {
+			continue
+		}
+		pushedHashes = append(pushedHashes, update.NewHash)
+
+		// new refs use the ignore file of the default branch
+		baseHash := update.OldHash
+		if baseHash == plumbing.ZeroHash {
+			head, err := repository.Head()
+			if err != nil {
+				continue
+			}
+			baseHash = head.Hash()
+		}
+		if !ignoreHashes[baseHash] {
+			ignoreHashes[baseHash] = true
+			ignoreList = ignoreList.Merge(loadCommitIgnoreList(sess, repository, baseHash))
+		}
+	}
+
+	commits, err := gitHandler.GetPushedCommits(repository, pushedHashes)
+	if err != nil {
+		return err
+	}
+	sess.Out.Debug("[HOOK][%s] Pushed commits: %d\n", repoName, len(commits))
+
+	for _, commit := range commits {
+		err = scanPushedCommit(sess, repository, repoName, commit, ignoreList)
+		if err != nil {
+			return err
+		}
+		sess.Stats.IncrementCommits()
+	}
+
+	return nil
+}
+
+// loadCommitIgnoreList loads the ignore file in the tree of a commit
+func loadCommitIgnoreList(sess *session.Session, repository *git.Repository, hash plumbing.Hash) *ignore.List {
+	commit, err := repository.CommitObject(hash)
+	if err != nil {
+		return nil
+	}
+	file, err := commit.File(ignore.DefaultFilename)
+	if err != nil {
+		return nil
+	}
+	reader, err := file.Reader()
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	repoIgnoreList, err := ignore.Parse(reader)
+	if err != nil {
+		sess.Out.Error("Unable to load %s of %s: %s\n", ignore.DefaultFilename, hash, err)
+		return nil
+	}
+	return repoIgnoreList
+}
+
+func scanPushedCommit(sess *session.Session, repository *git.Repository, repoName string, commit *object.Commit, ignoreList *ignore.List) error {
+	changes, err := gitHandler.GetChanges(commit, repository)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		action, err := change.Action()
+		if err != nil || action == merkletrie.Delete {
+			continue
+		}
+
+		p := gitHandler.GetChangePath(change)
+		patch, err := gitHandler.GetPatch(change)
+		if err != nil {
+			return err
+		}
+		content, addedLines := gitHandler.GetPatchContent(patch)
+
+		matchFile := signatures.NewMatchFile(p, content)
+		if matchFile.IsSkippable() {
+			sess.Out.Debug("[HOOK][%s] Skipping %s\n", repoName, matchFile.Path)
+			continue
+		}
+		isTestContext := matchFile.IsTestContext()
+		if isTestContext && *sess.Options.SkipTestContexts {
+			sess.Out.Debug("[HOOK][%s] Skipping %s\n", repoName, matchFile.Path)
+			continue
+		}
+
+		for _, signature := range sess.Prefilter.Select(matchFile) {
+			isContentSignature := signature.Part() == signatures.PartContent
+			if !isContentSignature && action != merkletrie.Insert {
+				continue
+			}
+
+			for _, match := range signature.Match(matchFile) {
+				if isContentSignature && !addedLines[match.Line] {
+					continue
+				}
+
+				finding := &findings.Finding{
+					FilePath:          p,
+					Action:            signature.Part(),
+					Description:       signature.Description(),
+					SignatureID:       signature.ID(),
+					Comment:           signature.Comment(),
+					Severity:          signature.Severity(),
+					Confidence:        signature.Confidence(),
+					Tags:              signature.Tags(),
+					RepositoryName:    repoName,
+					CommitHash:        commit.Hash.String(),
+					CommitMessage:     strings.TrimSpace(commit.Message),
+					CommitAuthor:      commit.Author.Name,
+					CommitAuthorEmail: commit.Author.Email,
+					CommitAuthorDate:  commit.Author.When.Format(time.RFC3339),
+					CommitDate:        commit.Committer.When.Format(time.RFC3339),
+					Line:              match.Line,
+					Column:            match.Column,
+					IsTestContext:     isTestContext,
+				}
+
+				setFindingSecret(sess, finding, match, matchFile)
+
+				if admitFinding(sess, repoName, finding, match, ignoreList) {
+					verifyFinding(context.Background(), sess, repoName, finding, match, matchFile)
+					sess.AddFinding(finding)
+					sess.Stats.IncrementFindings()
+				}
+			}
+		}
+		sess.Stats.IncrementFiles()
+	}
+
+	return nil
+}
+
+// PrintHookReport prints a compact, one line per finding report of the session findings
+func PrintHookReport(sess *session.Session) {
+	for _, finding := range sess.Findings {
+		location := finding.FilePath
+		if finding.Line > 0 {
+			location = fmt.Sprintf("%s:%d", location, finding.Line)
+		}
+		if len(finding.CommitHash) >= 8 {
+			location = fmt.Sprintf("%s %s", finding.CommitHash[:8], location)
+		}
+		if finding.SecretMasked != "" {
+			location = fmt.Sprintf("%s %s", location, finding.SecretMasked)
+		}
+		if finding.Verified == verify.StatusVerified {
+			location = fmt.Sprintf("%s (verified)", location)
+		}
+		sess.Out.Warn(" %s  [%s] %s\n", location, strings.ToUpper(finding.Severity), finding.Description)
+	}
+}
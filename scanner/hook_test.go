@@ -0,0 +1,96 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package scanner
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+
+	"github.com/grab/secret-scanner/scanner/ignore"
+	"github.com/grab/secret-scanner/scanner/options"
+	"github.com/grab/secret-scanner/scanner/signatures"
+	"github.com/grab/secret-scanner/scanner/stats"
+)
+
+func TestScanPushedCommits_IgnoreFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Fatalf("Cannot create temp. dir.: %v", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("Cannot init repository: %v", err)
+	}
+
+	commit := func(files map[string]string) plumbing.Hash {
+		worktree, err := repo.Worktree()
+		if err != nil {
+			t.Fatalf("Cannot get worktree: %v", err)
+		}
+		for filename, content := range files {
+			err = ioutil.WriteFile(path.Join(dir, filename), []byte(content), 0644)
+			if err != nil {
+				t.Fatalf("Cannot write file: %v", err)
+			}
+			_, err = worktree.Add(filename)
+			if err != nil {
+				t.Fatalf("Cannot add file: %v", err)
+			}
+		}
+		hash, err := worktree.Commit("update", &git.CommitOptions{
+			Author: &object.Signature{Name: "Jane Doe", Email: "jane@example.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatalf("Cannot commit: %v", err)
+		}
+		return hash
+	}
+	// push scans the commits of a push of master from the old to the new hash, and returns the findings
+	push := func(oldHash, newHash plumbing.Hash) int {
+		// the branch is only updated once the push is accepted
+		err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.Master, oldHash))
+		if err != nil {
+			t.Fatalf("Cannot set reference: %v", err)
+		}
+
+		sess := newTestSession(nil)
+		logSecret, skipTests, minSeverity := false, false, signatures.SeverityInfo
+		sess.Options = options.Options{LogSecret: &logSecret, SkipTestContexts: &skipTests, MinSeverity: &minSeverity}
+		sess.Stats = &stats.Stats{}
+		err = ScanPushedCommits(sess, dir, []*RefUpdate{{OldHash: oldHash, NewHash: newHash, Ref: "refs/heads/master"}})
+		if err != nil {
+			t.Fatalf("Want no err, got err: %v", err)
+		}
+		return len(sess.Findings)
+	}
+
+	base := commit(map[string]string{"README.md": "# readme\n"})
+
+	// a push cannot whitelist the secrets it adds
+	pushed := commit(map[string]string{
+		"config.yml":           "slack: " + testSlackToken + "\n",
+		ignore.DefaultFilename: ignore.PrefixPath + " config.yml\n",
+	})
+	if count := push(base, pushed); count != 1 {
+		t.Errorf("Want 1 finding, got %v", count)
+	}
+
+	// once accepted, the ignore file applies to later pushes
+	next := commit(map[string]string{"config.yml": "slack: " + testSlackToken + "\nslack_bot: " + testSlackToken + "\n"})
+	if count := push(pushed, next); count != 0 {
+		t.Errorf("Want 0 findings, got %v", count)
+	}
+}
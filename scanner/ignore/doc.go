@@ -0,0 +1,26 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package ignore
+
+const (
+	// DefaultFilename is the ignore file name read from the repository root
+	DefaultFilename = ".secretscannerignore"
+
+	// DefaultGlobalDir is the default directory name of the global ignore file
+	DefaultGlobalDir = ".secretscanner"
+
+	// PrefixFingerprint ignores findings by finding ID
+	PrefixFingerprint = "fingerprint:"
+
+	// PrefixSignature ignores findings by signature ID
+	PrefixSignature = "signature:"
+
+	// PrefixPath ignores findings by file path glob
+	PrefixPath = "path:"
+
+	// PrefixContent ignores findings by a regex over the matched content
+	PrefixContent = "content:"
+)
@@ -0,0 +1,245 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+
+	"github.com/grab/secret-scanner/scanner/findings"
+)
+
+// List contains rules for suppressing known false positives
+type List struct {
+	Fingerprints map[string]bool
+	Signatures   map[string]bool
+	Paths        []*Pattern
+	Contents     []*Pattern
+}
+
+// Pattern is a compiled ignore rule
+type Pattern struct {
+	Rule   string
+	Regexp *regexp.Regexp
+}
+
+// NewList creates an empty ignore list
+func NewList() *List {
+	return &List{
+		Fingerprints: map[string]bool{},
+		Signatures:   map[string]bool{},
+	}
+}
+
+// GetDefaultGlobalPath returns the default global ignore file path
+func GetDefaultGlobalPath() (string, error) {
+	userHomeDir, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(userHomeDir, DefaultGlobalDir, DefaultFilename), nil
+}
+
+// Load reads an ignore file, a missing file results in an empty list
+func Load(filepath string) (*List, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewList(), nil
+		}
+		return nil, err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(file)
+
+	list, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filepath, err)
+	}
+	return list, nil
+}
+
+// Parse parses ignore rules, one rule per line
+func Parse(r io.Reader) (*List, error) {
+	list := NewList()
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		err := list.Add(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Add adds a single rule, lines without prefix are treated as path globs
+func (l *List) Add(rule string) error {
+	switch {
+	case strings.HasPrefix(rule, PrefixFingerprint):
+		l.Fingerprints[strings.TrimSpace(strings.TrimPrefix(rule, PrefixFingerprint))] = true
+	case strings.HasPrefix(rule, PrefixSignature):
+		l.Signatures[strings.TrimSpace(strings.TrimPrefix(rule, PrefixSignature))] = true
+	case strings.HasPrefix(rule, PrefixContent):
+		re, err := regexp.Compile(strings.TrimSpace(strings.TrimPrefix(rule, PrefixContent)))
+		if err != nil {
+			return err
+		}
+		l.Contents = append(l.Contents, &Pattern{Rule: rule, Regexp: re})
+	default:
+		glob := strings.TrimSpace(strings.TrimPrefix(rule, PrefixPath))
+		if glob == "" {
+			return fmt.Errorf("empty path glob")
+		}
+		re, err := GlobToRegexp(glob)
+		if err != nil {
+			return err
+		}
+		l.Paths = append(l.Paths, &Pattern{Rule: rule, Regexp: re})
+	}
+	return nil
+}
+
+// AppendRule validates a rule and appends it to an ignore file, preceded by an optional comment.
+// The file and its directory are created if missing.
+func AppendRule(filepath, rule, comment string) error {
+	err := NewList().Add(rule)
+	if err != nil {
+		return err
+	}
+
+	var prefix string
+	content, err := ioutil.ReadFile(filepath)
+	switch {
+	case os.IsNotExist(err):
+		err = os.MkdirAll(path.Dir(filepath), 0700)
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case len(content) > 0 && content[len(content)-1] != '\n':
+		prefix = "\n"
+	}
+
+	file, err := os.OpenFile(filepath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(file)
+
+	if comment != "" {
+		prefix += "# " + strings.Replace(comment, "\n", " ", -1) + "\n"
+	}
+	_, err = file.WriteString(prefix + rule + "\n")
+	return err
+}
+
+// Merge returns a new list containing the rules of both lists
+func (l *List) Merge(other *List) *List {
+	merged := NewList()
+	for _, list := range []*List{l, other} {
+		if list == nil {
+			continue
+		}
+		for fp := range list.Fingerprints {
+			merged.Fingerprints[fp] = true
+		}
+		for sig := range list.Signatures {
+			merged.Signatures[sig] = true
+		}
+		merged.Paths = append(merged.Paths, list.Paths...)
+		merged.Contents = append(merged.Contents, list.Contents...)
+	}
+	return merged
+}
+
+// Match checks if a finding is suppressed, and returns the matching rule
+func (l *List) Match(finding *findings.Finding, matchContent string) (string, bool) {
+	if l == nil {
+		return "", false
+	}
+
+	if l.Fingerprints[finding.ID] {
+		return PrefixFingerprint + finding.ID, true
+	}
+
+	if finding.SignatureID != "" && l.Signatures[finding.SignatureID] {
+		return PrefixSignature + finding.SignatureID, true
+	}
+
+	for _, p := range l.Paths {
+		if p.Regexp.MatchString(finding.FilePath) {
+			return p.Rule, true
+		}
+	}
+
+	for _, p := range l.Contents {
+		if matchContent != "" && p.Regexp.MatchString(matchContent) {
+			return p.Rule, true
+		}
+	}
+
+	return "", false
+}
+
+// GlobToRegexp converts a path glob into a regex.
+// "*" and "?" do not match "/", "**" matches across directories.
+// Globs without "/" match a file or directory name at any depth.
+func GlobToRegexp(glob string) (*regexp.Regexp, error) {
+	glob = strings.TrimSuffix(glob, "/")
+
+	var sb strings.Builder
+	if strings.HasPrefix(glob, "/") || strings.Contains(glob, "/") {
+		glob = strings.TrimPrefix(glob, "/")
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("(^|/)")
+	}
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	// matching a directory suppresses everything underneath it
+	sb.WriteString("(/.*)?$")
+
+	return regexp.Compile(sb.String())
+}
@@ -8,29 +8,52 @@ package options
 import (
 	"flag"
 	"strings"
+
+	"github.com/grab/secret-scanner/scanner/signatures"
 )
 
 // Options ...
 type Options struct {
-	BaseURL          *string `json:"base_url"`
-	CommitDepth      *int    `json:"commit_depth"`
-	Debug            *bool   `json:"debug"`
-	EnvFilePath      *string `json:"env_file_path"`
-	GitProvider      *string `json:"git_provider"`
-	Load             *string `json:"-"`
-	LocalPath        *string `json:"local_path"`
-	LogSecret        *bool   `json:"log_secret"`
-	Report           *string `json:"-"`
-	Repos            *string `json:"repos"`
-	ScanTarget       *string `json:"scan_target"`
-	Silent           *bool   `json:"silent"`
-	SkipTestContexts *bool   `json:"skip_test_contexts"`
-	State            *bool   `json:"state"`
-	Threads          *int    `json:"threads"`
-	Token            *string `json:"token"`
-	UI               *bool   `json:"ui"`
-	UIHost           *string `json:"ui_host"`
-	UIPort           *string `json:"ui_port"`
+	AllRefs          *bool    `json:"all_refs"`
+	BaseURL          *string  `json:"base_url"`
+	BlobCache        *bool    `json:"blob_cache"`
+	CommitDepth      *int     `json:"commit_depth"`
+	Debug            *bool    `json:"debug"`
+	DisableRules     *string  `json:"disable_rules"`
+	EnableRules      *string  `json:"enable_rules"`
+	Entropy          *bool    `json:"entropy"`
+	EntropyBase64    *float64 `json:"entropy_base64"`
+	EntropyHex       *float64 `json:"entropy_hex"`
+	EntropyMinLength *int     `json:"entropy_min_length"`
+	EnvFilePath      *string  `json:"env_file_path"`
+	FailOn           *string  `json:"fail_on"`
+	Format           *string  `json:"format"`
+	GitProvider      *string  `json:"git_provider"`
+	IgnoreFile       *string  `json:"ignore_file"`
+	JSONL            *string  `json:"jsonl"`
+	Load             *string  `json:"-"`
+	LocalPath        *string  `json:"local_path"`
+	LogSecret        *bool    `json:"log_secret"`
+	MinSeverity      *string  `json:"min_severity"`
+	Org              *string  `json:"org"`
+	Report           *string  `json:"-"`
+	Repos            *string  `json:"repos"`
+	Rules            *string  `json:"rules"`
+	RulesReplace     *bool    `json:"rules_replace"`
+	ScanTarget       *string  `json:"scan_target"`
+	SecretSalt       *string  `json:"-"`
+	Silent           *bool    `json:"silent"`
+	SkipTestContexts *bool    `json:"skip_test_contexts"`
+	State            *bool    `json:"state"`
+	StateStore       *string  `json:"state_store"`
+	Tags             *string  `json:"tags"`
+	Threads          *int     `json:"threads"`
+	Token            *string  `json:"token"`
+	TrackFindings    *bool    `json:"track_findings"`
+	UI               *bool    `json:"ui"`
+	UIHost           *string  `json:"ui_host"`
+	UIPort           *string  `json:"ui_port"`
+	Verify           *bool    `json:"verify"`
 }
 
 // ParseScanTargets splits string of targets by comma
@@ -42,30 +65,85 @@ func (o Options) ParseScanTargets() []string {
 	return targets
 }
 
+// ParseRuleFiles splits string of rule file paths by comma
+func (o Options) ParseRuleFiles() []string {
+	return splitList(*o.Rules)
+}
+
+// ParseEnableRules splits string of enabled rule IDs by comma
+func (o Options) ParseEnableRules() []string {
+	return splitList(*o.EnableRules)
+}
+
+// ParseDisableRules splits string of disabled rule IDs by comma
+func (o Options) ParseDisableRules() []string {
+	return splitList(*o.DisableRules)
+}
+
+// ParseTags splits string of tags by comma
+func (o Options) ParseTags() []string {
+	return splitList(*o.Tags)
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		item = strings.Trim(item, " ")
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // Parse parses cmd params
 func Parse() (Options, error) {
 	options := Options{
+		AllRefs:          flag.Bool("all-refs", false, "If true, the full commit history of all branches and tags will be scanned"),
+		BlobCache:        flag.Bool("blob-cache", false, "If true, content without matches is matched only once, its hashes are kept in the state store for later scans"),
 		BaseURL:          flag.String("baseurl", "", "Specify Git provider base URL"),
 		CommitDepth:      flag.Int("commit-depth", 500, "Number of repository commits to process"),
 		Debug:            flag.Bool("debug", false, "Print debugging information"),
+		DisableRules:     flag.String("disable-rules", "", "Comma-separated list of rule IDs which are not matched"),
+		EnableRules:      flag.String("enable-rules", "", "Comma-separated list of rule IDs to match, all rules are matched by default"),
+		Entropy:          flag.Bool("entropy", false, "If true, strings with high Shannon entropy will be reported"),
+		EntropyBase64:    flag.Float64("entropy-base64", signatures.DefaultBase64EntropyThreshold, "Entropy threshold for base64 strings"),
+		EntropyHex:       flag.Float64("entropy-hex", signatures.DefaultHexEntropyThreshold, "Entropy threshold for hex strings"),
+		EntropyMinLength: flag.Int("entropy-min-length", signatures.DefaultEntropyMinLength, "Minimum length of strings checked for entropy"),
 		EnvFilePath:      flag.String("env", "", ".env file path containing Git provider base URLs and tokens"),
+		FailOn:           flag.String("fail-on", "", "Exit with status 1 if a finding has at least this severity (critical, high, medium, low, info)"),
+		Format:           flag.String("format", "json", "Format of the output file (json, sarif, html)"),
 		GitProvider:      flag.String("git", "github", "Name of git provider (Eg. github, gitlab, bitbucket)"),
-		Load:             flag.String("load", "", "Load session file"),
+		IgnoreFile:       flag.String("ignore-file", "", "Global ignore file suppressing known false positives (default ~/.secretscanner/.secretscannerignore)"),
+		JSONL:            flag.String("jsonl", "", "Append each finding as a JSON line to file as soon as it is found"),
+		Load:             flag.String("load", "", "Load a session saved as JSON report with -output instead of scanning"),
 		LocalPath:        flag.String("dir", "", "Specify the local git repo path to scan"),
 		LogSecret:        flag.Bool("log-secret", true, "If true, the matched secret will be included in report file"),
+		MinSeverity:      flag.String("min-severity", signatures.SeverityInfo, "Only report findings with at least this severity (critical, high, medium, low, info)"),
+		Org:              flag.String("org", "", "Github organization or user, Gitlab group or Bitbucket workspace whose repositories will be scanned"),
 		Report:           flag.String("output", "", "Save session to file"),
 		Repos:            flag.String("repos", "", "Comma-separated list of repos to scan"),
+		Rules:            flag.String("rules", "", "Comma-separated list of YAML/JSON rule files containing custom signatures"),
+		RulesReplace:     flag.Bool("rules-replace", false, "If true, custom rules replace the built-in signatures instead of being merged with them"),
 		ScanTarget:       flag.String("sub-dir", "", "Sub-directory within the repository to scan"),
+		SecretSalt:       flag.String("secret-salt", "", "Salt of the secret hashes in findings (default $SECRET_SCANNER_SALT, or a random salt kept in the state store)"),
 		Silent:           flag.Bool("quiet", false, "Suppress all output except for errors"),
 		SkipTestContexts: flag.Bool("skip-tests", true, "Skips possible test contexts"),
 		State:            flag.Bool("use-state", false, "If state is off, every scan will be treated as a brand new scan."),
+		StateStore:       flag.String("state-store", "", "State store URI, json://<path> or sqlite://<path>, SQLite requires a cgo build (default ~/.secretscanner/scan-histories.json)"),
+		Tags:             flag.String("tags", "", "Comma-separated list of tags, only rules with any of the tags are matched in addition to -enable-rules"),
 		Threads:          flag.Int("threads", 0, "Number of concurrent threads (default number of logical CPUs)"),
 		Token:            flag.String("token", "", "Specify Git provider token"),
-		//UI:               flag.Bool("ui", false, "Serves up local UI for scan results if true"),
-		//UIHost:           flag.String("ui-host", "127.0.0.1", "UI server host"),
-		//UIPort:           flag.String("ui-port", "8080", "UI server port"),
+		TrackFindings:    flag.Bool("track-findings", false, "If true, findings are tracked across scans in the state store, and only new findings are alerted. Findings are only resolved by full scans, not by -use-state or -all-refs scans"),
+		UI:               flag.Bool("ui", false, "Serves up local UI for scan results if true"),
+		UIHost:           flag.String("ui-host", "127.0.0.1", "UI server host"),
+		UIPort:           flag.String("ui-port", "8080", "UI server port"),
+		Verify:           flag.Bool("verify", false, "If true, found secrets are verified against the API of their issuer"),
 	}
 
+	flag.StringVar(options.Org, "group", "", "Alias of -org for Gitlab groups")
+
 	flag.Parse()
 
 	return options, nil
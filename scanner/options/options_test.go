@@ -24,3 +24,12 @@ func TestOptions_ParseScanTargets(t *testing.T) {
 		}
 	}
 }
+
+func TestOptions_ParseTags(t *testing.T) {
+	tags := "cloud, ,payment "
+	options := Options{Tags: &tags}
+	parsed := options.ParseTags()
+	if len(parsed) != 2 || parsed[0] != "cloud" || parsed[1] != "payment" {
+		t.Errorf("Want [cloud payment], got %v", parsed)
+	}
+}
@@ -6,11 +6,13 @@
 package scanner
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
@@ -24,24 +26,30 @@ import (
 	"github.com/grab/secret-scanner/scanner/findings"
 
 	gitHandler "github.com/grab/secret-scanner/common/git"
+	"github.com/grab/secret-scanner/scanner/blobcache"
 	"github.com/grab/secret-scanner/scanner/gitprovider"
+	"github.com/grab/secret-scanner/scanner/ignore"
 	"github.com/grab/secret-scanner/scanner/session"
 	"github.com/grab/secret-scanner/scanner/signatures"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 )
 
 // NewlineRegex ...
 var NewlineRegex = regexp.MustCompile(`\r?\n`)
 
-// Scan starts the scanning process
-func Scan(sess *session.Session, gitProvider gitprovider.GitProvider) {
+// Scan starts the scanning process.
+// If the context is cancelled, running workers stop after the current file or commit,
+// and no further repositories are scanned.
+func Scan(ctx context.Context, sess *session.Session, gitProvider gitprovider.GitProvider) {
 	if *sess.Options.LocalPath != "" {
-		LocalGitScan(sess, gitProvider)
-		sess.End()
+		LocalGitScan(ctx, sess, gitProvider)
+		endSession(ctx, sess)
 		return
 	}
 
-	gatherRepositories(sess, gitProvider)
+	gatherRepositories(ctx, sess, gitProvider)
 
 	sess.Stats.Status = session.StatusAnalyzing
 	var ch = make(chan *gitprovider.Repository, len(sess.Repositories))
@@ -80,71 +88,23 @@ func Scan(sess *session.Session, gitProvider gitprovider.GitProvider) {
 
 	for i := 0; i < threadNum; i++ {
 		go func(tid int) {
+			defer wg.Done()
 			for {
 				sess.Out.Debug("[THREAD #%d] Requesting new repository to analyze...\n", tid)
-				repo, ok := <-ch
-				if !ok {
-					sess.Out.Debug("[THREAD #%d] No more tasks, marking WaitGroup as done\n", tid)
-					wg.Done()
-					return
-				}
-
-				// Clone repo
-				sess.Out.Debug("[THREAD #%d][%s] Cloning repository...\n", tid, repo.FullName)
-				clone, cloneDir, err := gitHandler.CloneRepository(&repo.CloneURL, &repo.DefaultBranch, *sess.Options.CommitDepth, authMethod)
-				if err != nil {
-					if err.Error() != "Remote repository is empty" {
-						sess.Out.Error("Error cloning repository %s: %s\n", repo.FullName, err)
-					}
-					sess.Stats.IncrementRepositories()
-					sess.Stats.UpdateProgress(sess.Stats.Repositories, len(sess.Repositories))
-					continue
-				}
-				sess.Out.Debug("[THREAD #%d][%s] Cloned repository to: %s\n", tid, repo.FullName, cloneDir)
-
-				// Get checkpoint
-				sess.Out.Debug("[THREAD #%d][%s] Fetching the checkpoint.\n", tid, repo.FullName)
-				checkpoint := ""
-
-				if *sess.Options.State {
-					latestHistory := sess.StateStore.Get(*sess.Options.GitProvider, repo.ID)
-					if latestHistory != nil {
-						checkpoint = latestHistory.CommitHash
-					}
-				}
-
-				// Gather scan targets
-				targets := sess.Options.ParseScanTargets()
-				targetPaths, err := gitHandler.GatherPaths(cloneDir, repo.DefaultBranch, targets)
-				if err != nil {
-					sess.Out.Error("Failed to gather target paths for repo: %v", repo.FullName)
+				var repo *gitprovider.Repository
+				var ok bool
+				select {
+				case <-ctx.Done():
+					sess.Out.Debug("[THREAD #%d] Scan cancelled, marking WaitGroup as done\n", tid)
 					return
+				case repo, ok = <-ch:
 				}
-
-				targetPathMap := map[string]string{}
-				for _, tp := range targetPaths {
-					targetPathMap[path.Join(cloneDir, tp)] = tp
-				}
-
-				// Scan
-				scanRevisions(sess, repo, clone, checkpoint, cloneDir, targetPathMap)
-				latestCommitHash, err := gitHandler.GetLatestCommitHash(cloneDir)
-				if err != nil {
-					sess.Out.Error("Failed to get latest commit hash")
+				if !ok {
+					sess.Out.Debug("[THREAD #%d] No more tasks, marking WaitGroup as done\n", tid)
 					return
 				}
 
-				if *sess.Options.State {
-					err = sess.StateStore.Save(state.Create(*sess.Options.GitProvider, repo.ID, latestCommitHash, time.Now().String()))
-					if err != nil {
-						sess.Out.Error("Failed to save scan history: %v", err)
-					}
-				}
-
-				// Cleanup
-				sess.Out.Debug("[THREAD #%d][%s] Done analyzing commits\n", tid, repo.FullName)
-				_ = os.RemoveAll(cloneDir)
-				sess.Out.Debug("[THREAD #%d][%s] Deleted %s\n", tid, repo.FullName, cloneDir)
+				scanRepository(ctx, sess, repo, authMethod, tid)
 				sess.Stats.IncrementRepositories()
 				sess.Stats.UpdateProgress(sess.Stats.Repositories, len(sess.Repositories))
 			}
@@ -156,16 +116,104 @@ func Scan(sess *session.Session, gitProvider gitprovider.GitProvider) {
 	close(ch)
 	wg.Wait()
 
+	endSession(ctx, sess)
+}
+
+// scanRepository clones, scans and deletes a single repository
+func scanRepository(ctx context.Context, sess *session.Session, repo *gitprovider.Repository, authMethod transport.AuthMethod, tid int) {
+	// Clone repo
+	sess.Out.Debug("[THREAD #%d][%s] Cloning repository...\n", tid, repo.FullName)
+	clone, cloneDir, err := gitHandler.CloneRepository(ctx, &repo.CloneURL, &repo.DefaultBranch, *sess.Options.CommitDepth, *sess.Options.AllRefs, authMethod)
+	if err != nil {
+		if err.Error() != "Remote repository is empty" && ctx.Err() == nil {
+			sess.Out.Error("Error cloning repository %s: %s\n", repo.FullName, err)
+		}
+		return
+	}
+	sess.Out.Debug("[THREAD #%d][%s] Cloned repository to: %s\n", tid, repo.FullName, cloneDir)
+
+	// Cleanup
+	defer func() {
+		_ = os.RemoveAll(cloneDir)
+		sess.Out.Debug("[THREAD #%d][%s] Deleted %s\n", tid, repo.FullName, cloneDir)
+	}()
+
+	// Get checkpoint
+	sess.Out.Debug("[THREAD #%d][%s] Fetching the checkpoint.\n", tid, repo.FullName)
+	checkpoint := ""
+
+	if *sess.Options.State {
+		latestHistory := sess.StateStore.Get(*sess.Options.GitProvider, repo.ID)
+		if latestHistory != nil {
+			checkpoint = latestHistory.CommitHash
+		}
+	}
+
+	// Gather scan targets
+	targets := sess.Options.ParseScanTargets()
+	blobHashes, err := gitHandler.GatherBlobHashes(clone, repo.DefaultBranch, targets)
+	if err != nil {
+		sess.Out.Error("Failed to gather target paths for repo: %v\n", repo.FullName)
+		return
+	}
+
+	targetPathMap := map[string]string{}
+	for tp := range blobHashes {
+		targetPathMap[path.Join(cloneDir, tp)] = tp
+	}
+
+	ignoreList, err := loadRepoIgnoreList(sess, cloneDir)
+	if err != nil {
+		sess.Out.Error("Failed to load ignore file for repo %s: %v\n", repo.FullName, err)
+	}
+
+	// Scan
+	scanRevisions(ctx, sess, repo, clone, checkpoint, cloneDir, targetPathMap, blobHashes, ignoreList)
+	if ctx.Err() != nil {
+		// a partially scanned repository must be scanned again from the previous checkpoint
+		sess.Out.Debug("[THREAD #%d][%s] Scan cancelled\n", tid, repo.FullName)
+		return
+	}
+
+	latestCommitHash, err := gitHandler.GetLatestCommitHash(clone)
+	if err != nil {
+		sess.Out.Error("Failed to get latest commit hash\n")
+		return
+	}
+
+	if *sess.Options.State {
+		err = sess.StateStore.Save(state.Create(*sess.Options.GitProvider, repo.ID, latestCommitHash, time.Now().String()))
+		if err != nil {
+			sess.Out.Error("Failed to save scan history: %v", err)
+		}
+	}
+
+	saveFindingRecords(sess, repo, checkpoint, targets)
+
+	sess.Out.Debug("[THREAD #%d][%s] Done analyzing commits\n", tid, repo.FullName)
+}
+
+// endSession ends the session, and marks it as cancelled if the context is cancelled
+func endSession(ctx context.Context, sess *session.Session) {
 	sess.End()
+	if ctx.Err() != nil {
+		sess.Stats.Status = session.StatusCancelled
+	}
 }
 
 // LocalGitScan starts a scan on local directory without first cloning from git provider
-func LocalGitScan(sess *session.Session, gitProvider gitprovider.GitProvider) {
+func LocalGitScan(ctx context.Context, sess *session.Session, gitProvider gitprovider.GitProvider) {
 	sess.Stats.Status = session.StatusAnalyzing
 
-	// Gather scan targets
+	gitRepo, err := git.PlainOpen(*sess.Options.LocalPath)
+	if err != nil {
+		sess.Out.Error("Failed to open directory as git repo: %v", *sess.Options.LocalPath)
+		return
+	}
+
+	// Gather scan targets of the checked out revision
 	targets := sess.Options.ParseScanTargets()
-	targetPaths, err := gitHandler.GatherPaths(*sess.Options.LocalPath, "master", targets)
+	targetPaths, err := gitHandler.GatherPaths(gitRepo, "", targets)
 	if err != nil {
 		sess.Out.Error("Failed to gather target paths for repo: %v", *sess.Options.LocalPath)
 		return
@@ -188,7 +236,7 @@ func LocalGitScan(sess *session.Session, gitProvider gitprovider.GitProvider) {
 	repo := &gitprovider.Repository{
 		Owner:         "",
 		ID:            localID,
-		Name:          "",
+		Name:          localRepositoryName(gitRepo, *sess.Options.LocalPath),
 		FullName:      *sess.Options.LocalPath,
 		CloneURL:      "",
 		URL:           "",
@@ -197,12 +245,6 @@ func LocalGitScan(sess *session.Session, gitProvider gitprovider.GitProvider) {
 		Homepage:      "",
 	}
 
-	gitRepo, err := git.PlainOpen(*sess.Options.LocalPath)
-	if err != nil {
-		sess.Out.Error("Failed to open directory as git repo: %v", *sess.Options.LocalPath)
-		return
-	}
-
 	// Get checkpoint
 	checkpoint := ""
 	if *sess.Options.State {
@@ -212,13 +254,21 @@ func LocalGitScan(sess *session.Session, gitProvider gitprovider.GitProvider) {
 		}
 	}
 
-	// Scan
-	scanRevisions(sess, repo, gitRepo, checkpoint, *sess.Options.LocalPath, targetPathMap)
+	ignoreList, err := loadRepoIgnoreList(sess, *sess.Options.LocalPath)
+	if err != nil {
+		sess.Out.Error("Failed to load ignore file for repo %s: %v\n", repo.FullName, err)
+	}
+
+	// Scan, files of the working tree may differ from the committed blobs
+	scanRevisions(ctx, sess, repo, gitRepo, checkpoint, *sess.Options.LocalPath, targetPathMap, nil, ignoreList)
+	if ctx.Err() != nil {
+		return
+	}
 
 	sess.Stats.IncrementRepositories()
 	sess.Stats.UpdateProgress(sess.Stats.Repositories, len(sess.Repositories))
 
-	latestCommitHash, err := gitHandler.GetLatestCommitHash(*sess.Options.LocalPath)
+	latestCommitHash, err := gitHandler.GetLatestCommitHash(gitRepo)
 	if err != nil {
 		fmt.Println(err)
 	}
@@ -230,10 +280,12 @@ func LocalGitScan(sess *session.Session, gitProvider gitprovider.GitProvider) {
 		}
 	}
 
+	saveFindingRecords(sess, repo, checkpoint, targets)
+
 	// NO cleanup for local scan
 }
 
-func gatherRepositories(sess *session.Session, gitProvider gitprovider.GitProvider) {
+func gatherRepositories(ctx context.Context, sess *session.Session, gitProvider gitprovider.GitProvider) {
 	var repos []*gitprovider.Repository
 
 	if *sess.Options.Repos != "" {
@@ -251,7 +303,7 @@ func gatherRepositories(sess *session.Session, gitProvider gitprovider.GitProvid
 			} else {
 				opt["id"] = id
 			}
-			r, err := gitProvider.GetRepository(opt)
+			r, err := gitProvider.GetRepository(ctx, opt)
 			if err != nil {
 				sess.Out.Error("Error fetching the repo with ID %s: %s\n", id, err)
 				continue
@@ -259,6 +311,21 @@ func gatherRepositories(sess *session.Session, gitProvider gitprovider.GitProvid
 			repos = append(repos, r)
 		}
 	}
+
+	if *sess.Options.Org != "" {
+		opt := map[string]string{}
+		if gitProvider.Name() == gitprovider.GitlabName {
+			opt["group"] = *sess.Options.Org
+		} else {
+			opt["owner"] = *sess.Options.Org
+		}
+		rs, err := gitProvider.ListRepositories(ctx, opt)
+		if err != nil {
+			sess.Out.Error("Error listing the repos of %s: %s\n", *sess.Options.Org, err)
+		}
+		repos = append(repos, rs...)
+	}
+
 	for _, repo := range repos {
 		sess.Out.Info(" Retrieved repository: %s\n", repo.FullName)
 		sess.AddRepository(repo)
@@ -267,91 +334,116 @@ func gatherRepositories(sess *session.Session, gitProvider gitprovider.GitProvid
 	sess.Out.Info(" Retrieved %d %s from %s\n", len(repos), Pluralize(len(repos), "repository", "repositories"), *sess.Options.GitProvider)
 }
 
-func scanRevisions(sess *session.Session, repo *gitprovider.Repository, clone *git.Repository, checkpoint, cloneDir string, targetPathMap map[string]string) {
-	if checkpoint != "" {
-		scanGitCommits(sess, repo, clone, cloneDir, checkpoint, targetPathMap)
+// localRepositoryName returns the name of the worktree root of a local repository, as the pre-commit hook does
+func localRepositoryName(gitRepo *git.Repository, dir string) string {
+	if worktree, err := gitRepo.Worktree(); err == nil {
+		dir = worktree.Filesystem.Root()
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.Base(dir)
+	}
+	return filepath.Base(absDir)
+}
+
+// loadRepoIgnoreList merges the ignore file in the repository root with the global ignore list
+func loadRepoIgnoreList(sess *session.Session, dir string) (*ignore.List, error) {
+	repoIgnoreList, err := ignore.Load(path.Join(dir, ignore.DefaultFilename))
+	if err != nil {
+		return sess.IgnoreList, err
+	}
+	return sess.IgnoreList.Merge(repoIgnoreList), nil
+}
+
+func scanRevisions(ctx context.Context, sess *session.Session, repo *gitprovider.Repository, clone *git.Repository, checkpoint, cloneDir string, targetPathMap map[string]string, blobHashes map[string]plumbing.Hash, ignoreList *ignore.List) {
+	if checkpoint != "" || *sess.Options.AllRefs {
+		scanGitCommits(ctx, sess, repo, clone, cloneDir, checkpoint, targetPathMap, ignoreList)
 	} else {
-		scanCurrentGitRevision(sess, repo, cloneDir, targetPathMap)
+		scanCurrentGitRevision(ctx, sess, repo, cloneDir, targetPathMap, blobHashes, ignoreList)
 	}
 }
 
 // scanCurrentGitRevision runs the file scan for complete gitlab repo.
 // It scans only the lastest revision. rather than scanning the entire commit history
-func scanCurrentGitRevision(sess *session.Session, repo *gitprovider.Repository, dir string, targetPathMap map[string]string) {
+// blobHashes are the git blob hashes of the checked out files by path, if the files are known to be unmodified.
+func scanCurrentGitRevision(ctx context.Context, sess *session.Session, repo *gitprovider.Repository, dir string, targetPathMap map[string]string, blobHashes map[string]plumbing.Hash, ignoreList *ignore.List) {
 	sess.Out.Debug("[THREAD][%s] Fetching repository files of: %s\n", repo.FullName, dir)
 	for absPath, subPath := range targetPathMap {
+		if ctx.Err() != nil {
+			return
+		}
 		sess.Out.Debug("Path: %s\n", absPath)
 		content, err := ioutil.ReadFile(absPath)
 		if err != nil {
 			sess.Out.Error("[FILE NOT FOUND]: %s\n", absPath)
+			sess.SkipPath(repo.ID, subPath)
 			continue
 		}
 		matchFile := signatures.NewMatchFile(subPath, string(content))
 		if matchFile.IsSkippable() {
 			sess.Out.Debug("[THREAD][%s] Skipping %s\n", repo.FullName, matchFile.Path)
+			sess.SkipPath(repo.ID, subPath)
 			continue
 		}
 		isTestContext := matchFile.IsTestContext()
 		if isTestContext && *sess.Options.SkipTestContexts {
 			sess.Out.Debug("[THREAD][%s] Skipping %s\n", repo.FullName, matchFile.Path)
+			sess.SkipPath(repo.ID, subPath)
 			continue
 		}
 		sess.Out.Debug("[THREAD][%s] Matching: %s...\n", repo.FullName, matchFile.Path)
-		for _, signature := range sess.Signatures {
-			matches := signature.Match(matchFile)
-
-			for _, match := range matches {
-				finding := &findings.Finding{
-					FilePath:       subPath,
-					Action:         signature.Part(),
-					Description:    signature.Description(),
-					Comment:        signature.Comment(),
-					RepositoryName: repo.Name,
-					RepositoryURL:  repo.URL,
-					FileURL:        fmt.Sprintf("%s/blob/%s/%s", repo.URL, repo.DefaultBranch, subPath),
-					Line:           match.Line,
-					IsTestContext:  isTestContext,
-				}
+		blobHash := ""
+		if hash, ok := blobHashes[subPath]; ok {
+			blobHash = hash.String()
+		}
+		for _, m := range matchSignatures(sess, matchFile, blobHash) {
+			signature, match := m.signature, m.match
+			finding := &findings.Finding{
+				FilePath:       subPath,
+				Action:         signature.Part(),
+				Description:    signature.Description(),
+				SignatureID:    signature.ID(),
+				Comment:        signature.Comment(),
+				Severity:       signature.Severity(),
+				Confidence:     signature.Confidence(),
+				Tags:           signature.Tags(),
+				RepositoryName: repo.Name,
+				RepositoryURL:  repo.URL,
+				FileURL:        fmt.Sprintf("%s/blob/%s/%s", repo.URL, repo.DefaultBranch, subPath),
+				Line:           match.Line,
+				Column:         match.Column,
+				IsTestContext:  isTestContext,
+			}
 
-				if *sess.Options.LogSecret {
-					finding.LineContent = match.LineContent
-					finding.TruncateLineContent(findings.MaxLineChar)
-				}
+			setFindingSecret(sess, finding, match, matchFile)
 
-				hashID, err := finding.GenerateHashID()
-				if err != nil {
-					sess.Out.Error("Unable to generate hash ID for %v, skipping...", finding.FileURL)
-					continue
-				}
-				finding.ID = hashID
-
-				sess.AddFinding(finding)
-
-				sess.Out.Warn(" %s: %s\n", strings.ToUpper(session.PathScan), finding.Description)
-				sess.Out.Info("  Path........: %s\n", finding.FilePath)
-				sess.Out.Info("  Repo........: %s\n", repo.FullName)
-				sess.Out.Info("  Author......: %s\n", finding.CommitAuthor)
-				sess.Out.Info("  Comment.....: %s\n", finding.Comment)
-				sess.Out.Info("  File URL....: %s\n", finding.FileURL)
-				sess.Out.Info("  Line........: %v\n", finding.Line)
-				sess.Out.Info(" ------------------------------------------------\n\n")
-				sess.Stats.IncrementFindings()
-			}
+			addFinding(ctx, sess, repo, finding, match, matchFile, ignoreList)
 		}
 	}
 }
 
 // scanGitCommits run a scan to analyze the diffs present in the commit history
-// It will scan the commit history till the checkpoint (last scanned commit) is reached
-func scanGitCommits(sess *session.Session, repo *gitprovider.Repository, clone *git.Repository, dir, checkpoint string, targetPathMap map[string]string) {
-	commitHistories, err := gitHandler.GetRepositoryHistory(clone)
+// It will scan the commit history till the checkpoint (last scanned commit) is reached.
+// With the all-refs option, every commit reachable from any branch or tag is scanned once.
+func scanGitCommits(ctx context.Context, sess *session.Session, repo *gitprovider.Repository, clone *git.Repository, dir, checkpoint string, targetPathMap map[string]string, ignoreList *ignore.List) {
+	var commitHistories []*object.Commit
+	var err error
+	if *sess.Options.AllRefs {
+		commitHistories, err = gitHandler.GetAllRefsHistory(clone, checkpoint)
+	} else {
+		commitHistories, err = gitHandler.GetRepositoryHistory(clone)
+	}
 	if err != nil {
 		sess.Out.Error("[THREAD][%s] Error getting commit history: %s\n", repo.FullName, err)
 		return
 	}
 	sess.Out.Debug("[THREAD][%s] Number of commits: %d\n", repo.FullName, len(commitHistories))
+	targets := sess.Options.ParseScanTargets()
 
 	for _, commit := range commitHistories {
+		if ctx.Err() != nil {
+			return
+		}
 		if strings.TrimSpace(commit.Hash.String()) == strings.TrimSpace(checkpoint) {
 			sess.Out.Debug("\nCheckpoint Reached !!\n")
 			break
@@ -362,9 +454,16 @@ func scanGitCommits(sess *session.Session, repo *gitprovider.Repository, clone *
 		for _, change := range changes {
 			p := gitHandler.GetChangePath(change)
 
-			_, exists := targetPathMap[path.Join(dir, p)]
-			if len(targetPathMap) > 0 && !exists {
-				continue
+			if *sess.Options.AllRefs {
+				// files of other branches or deleted files are not in the target paths
+				if !inScanTargets(p, targets) {
+					continue
+				}
+			} else {
+				_, exists := targetPathMap[path.Join(dir, p)]
+				if len(targetPathMap) > 0 && !exists {
+					continue
+				}
 			}
 
 			allContent := ""
@@ -392,91 +491,36 @@ func scanGitCommits(sess *session.Session, repo *gitprovider.Repository, clone *
 				continue
 			}
 			sess.Out.Debug("[THREAD][%s] Matching: %s...\n", repo.FullName, matchFile.Path)
-			for _, signature := range sess.Signatures {
-				matches := signature.Match(matchFile)
-
-				for _, match := range matches {
-					finding := &findings.Finding{
-						FilePath:       p,
-						Action:         signature.Part(),
-						Description:    signature.Description(),
-						Comment:        signature.Comment(),
-						RepositoryName: repo.Name,
-						RepositoryURL:  repo.URL,
-						FileURL:        fmt.Sprintf("%s/blob/%s/%s", repo.URL, repo.DefaultBranch, p),
-						Line:           match.Line,
-						IsTestContext:  isTestContext,
-					}
-
-					if *sess.Options.LogSecret {
-						finding.LineContent = match.LineContent
-						finding.TruncateLineContent(findings.MaxLineChar)
-					}
-
-					hashID, err := finding.GenerateHashID()
-					if err != nil {
-						sess.Out.Error("Unable to generate hash ID for %v, skipping...", finding.FileURL)
-						continue
-					}
-					finding.ID = hashID
-
-					sess.AddFinding(finding)
-
-					sess.Out.Warn(" %s: %s\n", strings.ToUpper(session.PathScan), finding.Description)
-					sess.Out.Info("  Path........: %s\n", finding.FilePath)
-					sess.Out.Info("  Repo........: %s\n", repo.FullName)
-					sess.Out.Info("  Author......: %s\n", finding.CommitAuthor)
-					sess.Out.Info("  Comment.....: %s\n", finding.Comment)
-					sess.Out.Info("  File URL....: %s\n", finding.FileURL)
-					sess.Out.Info("  Line........: %v\n", finding.Line)
-					sess.Out.Info(" ------------------------------------------------\n\n")
-					sess.Stats.IncrementFindings()
+			// the added lines of a change are not a blob, they are cached by the hash of the content
+			for _, m := range matchSignatures(sess, matchFile, "") {
+				signature, match := m.signature, m.match
+				finding := &findings.Finding{
+					FilePath:          p,
+					Action:            signature.Part(),
+					Description:       signature.Description(),
+					SignatureID:       signature.ID(),
+					Comment:           signature.Comment(),
+					Severity:          signature.Severity(),
+					Confidence:        signature.Confidence(),
+					Tags:              signature.Tags(),
+					RepositoryName:    repo.Name,
+					CommitHash:        commit.Hash.String(),
+					CommitMessage:     strings.TrimSpace(commit.Message),
+					CommitAuthor:      commit.Author.Name,
+					CommitAuthorEmail: commit.Author.Email,
+					CommitAuthorDate:  commit.Author.When.Format(time.RFC3339),
+					CommitDate:        commit.Committer.When.Format(time.RFC3339),
+					RepositoryURL:     repo.URL,
+					FileURL:           fmt.Sprintf("%s/blob/%s/%s", repo.URL, repo.DefaultBranch, p),
+					CommitURL:         fmt.Sprintf("%s/commit/%s", repo.URL, commit.Hash.String()),
+					Line:              match.Line,
+					Column:            match.Column,
+					IsTestContext:     isTestContext,
 				}
 
-				//if signature.Match(matchFile) {
-				//	latestContent, err := ioutil.ReadFile(path.Join(dir, p))
-				//	if err != nil {
-				//		sess.Out.Info("[LATEST FILE NOT FOUND]: %s/%s\n", dir, p)
-				//		continue
-				//	}
-				//	matchFile = signatures.NewMatchFile(p, string(latestContent))
-				//	if signature.Match(matchFile) {
-				//		finding := &findings.Finding{
-				//			FilePath:       p,
-				//			Action:         session.ContentScan,
-				//			Description:    signature.Description(),
-				//			Comment:        signature.Comment(),
-				//			RepositoryName: repo.Name,
-				//			CommitHash:     commit.Hash.String(),
-				//			CommitMessage:  strings.TrimSpace(commit.Message),
-				//			CommitAuthor:   commit.Author.String(),
-				//			RepositoryURL:  repo.URL,
-				//			FileURL:        fmt.Sprintf("%s/blob/%s/%s", repo.URL, repo.DefaultBranch, p),
-				//			CommitURL:      fmt.Sprintf("%s/commit/%s", repo.URL, commit.Hash.String()),
-				//			IsTestContext:  isTestContext,
-				//		}
-				//
-				//		hashID, err := finding.GenerateHashID()
-				//		if err != nil {
-				//			sess.Out.Error("Unable to generate hash ID for %v, skipping...", finding.FileURL)
-				//			continue
-				//		}
-				//		finding.ID = hashID
-				//
-				//		sess.AddFinding(finding)
-				//
-				//		sess.Out.Warn(" %s: %s\n", strings.ToUpper(session.ContentScan), finding.Description)
-				//		sess.Out.Info("  Path.......: %s\n", finding.FilePath)
-				//		sess.Out.Info("  Repo.......: %s\n", repo.FullName)
-				//		sess.Out.Info("  Message....: %s\n", TruncateString(finding.CommitMessage, 100))
-				//		sess.Out.Info("  Author.....: %s\n", finding.CommitAuthor)
-				//		sess.Out.Info("  Comment....: %s\n", finding.Comment)
-				//		sess.Out.Info("  File URL...: %s\n", finding.FileURL)
-				//		sess.Out.Info("  Commit URL.: %s\n", finding.CommitURL)
-				//		sess.Out.Info(" ------------------------------------------------\n\n")
-				//		sess.Stats.IncrementFindings()
-				//	}
-				//}
+				setFindingSecret(sess, finding, match, matchFile)
+
+				addFinding(ctx, sess, repo, finding, match, matchFile, ignoreList)
 			}
 			sess.Stats.IncrementFiles()
 		}
@@ -485,6 +529,147 @@ func scanGitCommits(sess *session.Session, repo *gitprovider.Repository, clone *
 	}
 }
 
+// setFindingSecret adds the masked and hashed secret of a match to a finding,
+// the secret itself, the matched content and its surrounding lines are only added if secrets are logged
+func setFindingSecret(sess *session.Session, finding *findings.Finding, match *signatures.MatchResult, matchFile signatures.MatchFile) {
+	finding.SetSecret(match.Secret, sess.SecretSalt)
+	if *sess.Options.LogSecret {
+		finding.Secret = match.Secret
+		finding.LineContent = match.LineContent
+		finding.TruncateLineContent(findings.MaxLineChar)
+		if finding.Line > 0 {
+			finding.SetContext(matchFile.Context(finding.Line, findings.ContextRadius))
+		}
+	}
+}
+
+// verifyFinding sets the verification status of the secret of a finding if verification is enabled
+func verifyFinding(ctx context.Context, sess *session.Session, source string, finding *findings.Finding, match *signatures.MatchResult, matchFile signatures.MatchFile) {
+	status, err := sess.Verifier.Verify(ctx, finding.SignatureID, match.Secret, matchFile.ContentRaw)
+	if err != nil {
+		sess.Out.Debug("[THREAD][%s] Unable to verify %s in %s: %v\n", source, finding.Description, finding.FilePath, err)
+	}
+	finding.Verified = status
+}
+
+// signatureMatch is a match result of a signature
+type signatureMatch struct {
+	signature signatures.Signature
+	match     *signatures.MatchResult
+}
+
+// matchSignatures matches a file against the signatures of the session.
+// Content signatures are skipped for content known from the blob cache to have no matches,
+// or if none of their keywords occurs in the content.
+// The cache is keyed on the git blob hash of the file, or on the hash of the content if blobHash is empty.
+func matchSignatures(sess *session.Session, matchFile signatures.MatchFile, blobHash string) []signatureMatch {
+	contentHash := blobHash
+	if contentHash == "" && sess.BlobCache != nil {
+		contentHash = blobcache.Hash(matchFile.ContentRaw)
+	}
+	isClean := sess.BlobCache.IsClean(contentHash)
+	hasContentMatch := false
+
+	sigs := sess.Signatures
+	if !isClean {
+		sigs = sess.Prefilter.Select(matchFile)
+	}
+
+	var matches []signatureMatch
+	for _, signature := range sigs {
+		isContentSignature := signature.Part() == signatures.PartContent
+		if isContentSignature && isClean {
+			continue
+		}
+
+		for _, match := range signature.Match(matchFile) {
+			matches = append(matches, signatureMatch{signature: signature, match: match})
+			hasContentMatch = hasContentMatch || isContentSignature
+		}
+	}
+
+	if !isClean && !hasContentMatch {
+		sess.BlobCache.MarkClean(contentHash)
+	}
+	return matches
+}
+
+// saveFindingRecords saves the lifecycle of the findings of a scanned repository.
+// Findings are only resolved by a scan of all files at the latest revision,
+// a scan from a checkpoint or of all refs does not see every finding still present.
+func saveFindingRecords(sess *session.Session, repo *gitprovider.Repository, checkpoint string, targets []string) {
+	if !*sess.Options.TrackFindings {
+		return
+	}
+
+	resolve := checkpoint == "" && !*sess.Options.AllRefs
+	err := sess.SaveFindingRecords(repo.ID, resolve, func(p string) bool {
+		return inScanTargets(p, targets)
+	})
+	if err != nil {
+		sess.Out.Error("Failed to save findings of repo %s: %v\n", repo.FullName, err)
+	}
+}
+
+// inScanTargets checks if a file path is within one of the scan target sub-directories
+func inScanTargets(p string, targets []string) bool {
+	for _, t := range targets {
+		t = strings.Trim(t, "/")
+		if t == "" || p == t || strings.HasPrefix(p, t+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// addFinding assigns the finding ID and adds the finding to the session unless it is ignored
+func addFinding(ctx context.Context, sess *session.Session, repo *gitprovider.Repository, finding *findings.Finding, match *signatures.MatchResult, matchFile signatures.MatchFile, ignoreList *ignore.List) {
+	if !admitFinding(sess, repo.FullName, finding, match, ignoreList) {
+		// a suppressed secret is still present, and must not be resolved
+		sess.KeepFinding(repo.ID, finding)
+		return
+	}
+	verifyFinding(ctx, sess, repo.FullName, finding, match, matchFile)
+	sess.TrackFinding(repo.ID, finding)
+
+	sess.AddFinding(finding)
+
+	// findings of earlier scans are still reported, but only new ones are alerted
+	if finding.Status == state.FindingStatusExisting {
+		sess.Out.Debug("[THREAD][%s] Existing finding %s in %s, first seen %s\n", repo.FullName, finding.ID, finding.FilePath, finding.FirstSeen)
+		sess.Stats.IncrementFindings()
+		return
+	}
+
+	sess.Out.Warn(" %s: [%s] %s\n", strings.ToUpper(session.PathScan), strings.ToUpper(finding.Severity), finding.Description)
+	sess.Out.Info("  Path........: %s\n", finding.FilePath)
+	sess.Out.Info("  Repo........: %s\n", repo.FullName)
+	if finding.CommitHash != "" {
+		sess.Out.Info("  Commit......: %s\n", finding.CommitHash)
+		sess.Out.Info("  Message.....: %s\n", TruncateString(finding.CommitMessage, 100))
+		sess.Out.Info("  Author......: %s <%s>\n", finding.CommitAuthor, finding.CommitAuthorEmail)
+		sess.Out.Info("  Date........: %s\n", finding.CommitDate)
+	}
+	if finding.SignatureID != "" {
+		sess.Out.Info("  Rule........: %s\n", finding.SignatureID)
+	}
+	sess.Out.Info("  Comment.....: %s\n", finding.Comment)
+	sess.Out.Info("  Confidence..: %s\n", finding.Confidence)
+	sess.Out.Info("  File URL....: %s\n", finding.FileURL)
+	if finding.CommitURL != "" {
+		sess.Out.Info("  Commit URL..: %s\n", finding.CommitURL)
+	}
+	sess.Out.Info("  Line........: %v\n", finding.Line)
+	if finding.SecretMasked != "" {
+		sess.Out.Info("  Secret......: %s\n", finding.SecretMasked)
+	}
+	if finding.Verified != "" {
+		sess.Out.Info("  Verified....: %s\n", finding.Verified)
+	}
+	sess.Out.Info(" ------------------------------------------------\n\n")
+	sess.Stats.IncrementFindings()
+}
+
 // Pluralize makes word plural
 func Pluralize(count int, singular string, plural string) string {
 	if count == 1 {
@@ -502,3 +687,32 @@ func TruncateString(str string, maxLength int) string {
 	}
 	return str
 }
+
+// admitFinding assigns the finding ID and checks the inline allow comment and ignore list.
+// Suppressed findings are added to the ignored findings of the session, and false is returned.
+func admitFinding(sess *session.Session, source string, finding *findings.Finding, match *signatures.MatchResult, ignoreList *ignore.List) bool {
+	hashID, err := finding.GenerateHashID()
+	if err != nil {
+		sess.Out.Error("Unable to generate hash ID for %v, skipping...", finding.FileURL)
+		return false
+	}
+	finding.ID = hashID
+
+	if signatures.SeverityRank(finding.Severity) < signatures.SeverityRank(*sess.Options.MinSeverity) {
+		sess.Out.Debug("[THREAD][%s] Skipping %s severity %s in %s\n", source, finding.Severity, finding.Description, finding.FilePath)
+		return false
+	}
+
+	if match.Allowed {
+		finding.IgnoreReason = strings.TrimSpace(fmt.Sprintf("%s %s", signatures.AllowMarker, match.AllowReason))
+	} else if rule, ignored := ignoreList.Match(finding, match.LineContent); ignored {
+		finding.IgnoreReason = fmt.Sprintf("%s %s", ignore.DefaultFilename, rule)
+	}
+	if finding.IgnoreReason != "" {
+		sess.Out.Debug("[THREAD][%s] Ignoring %s in %s (%s)\n", source, finding.Description, finding.FilePath, finding.IgnoreReason)
+		sess.AddIgnoredFinding(finding)
+		return false
+	}
+
+	return true
+}
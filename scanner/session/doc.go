@@ -18,9 +18,60 @@ const (
 	// StatusFinished ...
 	StatusFinished = "finished"
 
+	// StatusCancelled ...
+	StatusCancelled = "cancelled"
+
 	// ContentScan ...
 	ContentScan = "Content Scan"
 
 	// PathScan ...
 	PathScan = "Path Scan"
+
+	// SecretSaltEnv is the environment variable holding the salt of the secret hashes
+	SecretSaltEnv = "SECRET_SCANNER_SALT"
+
+	// FormatJSON ...
+	FormatJSON = "json"
+
+	// FormatSARIF ...
+	FormatSARIF = "sarif"
+
+	// FormatHTML ...
+	FormatHTML = "html"
+
+	// SARIFSchema is the SARIF 2.1.0 JSON schema
+	SARIFSchema = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+	// SARIFVersion ...
+	SARIFVersion = "2.1.0"
+
+	// SARIFToolName ...
+	SARIFToolName = "secret-scanner"
+
+	// SARIFToolInformationURI ...
+	SARIFToolInformationURI = "https://github.com/grab/secret-scanner"
+
+	// SARIFColumnKind is the unit of the result columns, columns of findings count Unicode code points
+	SARIFColumnKind = "unicodeCodePoints"
+
+	// SARIFLevelError ...
+	SARIFLevelError = "error"
+
+	// SARIFLevelWarning ...
+	SARIFLevelWarning = "warning"
+
+	// SARIFLevelNote ...
+	SARIFLevelNote = "note"
+
+	// SARIFBaselineStateNew is the baseline state of findings not seen in an earlier scan
+	SARIFBaselineStateNew = "new"
+
+	// SARIFBaselineStateUnchanged is the baseline state of findings seen in an earlier scan
+	SARIFBaselineStateUnchanged = "unchanged"
+
+	// SARIFFingerprintKey is the partial fingerprint key holding the finding ID
+	SARIFFingerprintKey = "secretScannerFindingId/v1"
+
+	// SARIFSecretFingerprintKey is the partial fingerprint key holding the salted secret hash
+	SARIFSecretFingerprintKey = "secretScannerSecretHash/v1"
 )
@@ -0,0 +1,237 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package session
+
+import (
+	"bytes"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grab/secret-scanner/scanner/findings"
+	"github.com/grab/secret-scanner/scanner/signatures"
+	"github.com/grab/secret-scanner/scanner/stats"
+)
+
+// HTMLReport is the data of the HTML report
+type HTMLReport struct {
+	GeneratedAt     time.Time
+	Stats           *stats.Stats
+	SeverityCounts  []HTMLSeverityCount
+	Repositories    []*HTMLRepository
+	FindingCount    int
+	IgnoredFindings int
+}
+
+// HTMLSeverityCount is the number of findings of a severity
+type HTMLSeverityCount struct {
+	Severity string
+	Count    int
+}
+
+// HTMLRepository groups the findings of a repository by signature
+type HTMLRepository struct {
+	Name         string
+	URL          string
+	FindingCount int
+	Groups       []*HTMLSignatureGroup
+}
+
+// HTMLSignatureGroup holds the findings of a signature in a repository
+type HTMLSignatureGroup struct {
+	SignatureID string
+	Description string
+	Comment     string
+	Severity    string
+	Findings    []*findings.Finding
+}
+
+var htmlFuncs = template.FuncMap{
+	"upper":      strings.ToUpper,
+	"lineNumber": func(start uint64, i int) uint64 { return start + uint64(i) },
+	"highlight":  highlightSecret,
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(htmlFuncs).Parse(htmlReportTemplate))
+
+// ToHTMLReport groups the session findings by repository, and by signature ordered by severity
+func (s *Session) ToHTMLReport() *HTMLReport {
+	report := &HTMLReport{
+		GeneratedAt:     time.Now(),
+		Stats:           s.Stats,
+		FindingCount:    len(s.Findings),
+		IgnoredFindings: len(s.IgnoredFindings),
+	}
+
+	severityCounts := map[string]int{}
+	repositories := map[string]*HTMLRepository{}
+	groups := map[string]*HTMLSignatureGroup{}
+	for _, finding := range s.Findings {
+		severityCounts[finding.Severity]++
+
+		repo, ok := repositories[finding.RepositoryName]
+		if !ok {
+			repo = &HTMLRepository{Name: finding.RepositoryName, URL: finding.RepositoryURL}
+			repositories[finding.RepositoryName] = repo
+			report.Repositories = append(report.Repositories, repo)
+		}
+		repo.FindingCount++
+
+		signatureKey := finding.SignatureID
+		if signatureKey == "" {
+			signatureKey = finding.Description
+		}
+		groupKey := finding.RepositoryName + "\x00" + signatureKey
+		group, ok := groups[groupKey]
+		if !ok {
+			group = &HTMLSignatureGroup{
+				SignatureID: finding.SignatureID,
+				Description: finding.Description,
+				Comment:     finding.Comment,
+				Severity:    finding.Severity,
+			}
+			groups[groupKey] = group
+			repo.Groups = append(repo.Groups, group)
+		}
+		group.Findings = append(group.Findings, finding)
+	}
+
+	// most severe first
+	for i := len(signatures.Severities) - 1; i >= 0; i-- {
+		severity := signatures.Severities[i]
+		if count := severityCounts[severity]; count > 0 {
+			report.SeverityCounts = append(report.SeverityCounts, HTMLSeverityCount{Severity: severity, Count: count})
+		}
+	}
+
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		return report.Repositories[i].Name < report.Repositories[j].Name
+	})
+	for _, repo := range report.Repositories {
+		sort.SliceStable(repo.Groups, func(i, j int) bool {
+			a, b := repo.Groups[i], repo.Groups[j]
+			if rankA, rankB := signatures.SeverityRank(a.Severity), signatures.SeverityRank(b.Severity); rankA != rankB {
+				return rankA > rankB
+			}
+			return a.Description < b.Description
+		})
+		for _, group := range repo.Groups {
+			sort.SliceStable(group.Findings, func(i, j int) bool {
+				a, b := group.Findings[i], group.Findings[j]
+				if a.FilePath != b.FilePath {
+					return a.FilePath < b.FilePath
+				}
+				return a.Line < b.Line
+			})
+		}
+	}
+
+	return report
+}
+
+// ToHTML renders the session findings as a self-contained HTML page
+func (s *Session) ToHTML() ([]byte, error) {
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, s.ToHTMLReport())
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SaveHTMLToFile exports scan results to file as HTML report
+func (s *Session) SaveHTMLToFile(location string) (string, error) {
+	html, err := s.ToHTML()
+	if err != nil {
+		return "", err
+	}
+	return writeReportFile(location, html)
+}
+
+// highlightSecret escapes a line and marks the occurrences of the secret
+func highlightSecret(line, secret string) template.HTML {
+	if secret == "" {
+		return template.HTML(template.HTMLEscapeString(line))
+	}
+
+	parts := strings.Split(line, secret)
+	for i, part := range parts {
+		parts[i] = template.HTMLEscapeString(part)
+	}
+	return template.HTML(strings.Join(parts, "<mark>"+template.HTMLEscapeString(secret)+"</mark>"))
+}
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Secret Scanner Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+h1, h2, h3 { font-weight: 600; }
+h2 { border-bottom: 1px solid #e1e4e8; padding-bottom: .3em; margin-top: 2em; }
+a { color: #0366d6; }
+table.summary td, table.summary th { padding: .2em 1em .2em 0; text-align: left; }
+details { border: 1px solid #e1e4e8; border-radius: 6px; margin: .5em 0; padding: .5em 1em; }
+summary { cursor: pointer; }
+.finding { border-top: 1px solid #e1e4e8; padding: .8em 0; }
+.meta { font-size: .9em; color: #586069; }
+.meta span { margin-right: 1.5em; }
+.severity { display: inline-block; border-radius: 3px; padding: 0 .5em; font-size: .8em; font-weight: 600; color: #fff; }
+.severity-critical { background: #86181d; }
+.severity-high { background: #d73a49; }
+.severity-medium { background: #e36209; }
+.severity-low { background: #0366d6; }
+.severity-info { background: #6a737d; }
+pre { background: #f6f8fa; border-radius: 6px; padding: .5em 0; overflow-x: auto; font-size: .85em; }
+pre .line { display: block; padding: 0 1em; }
+pre .line.match { background: #fffbdd; }
+pre .number { display: inline-block; min-width: 4em; color: #959da5; user-select: none; }
+mark { background: #ffdf5d; }
+</style>
+</head>
+<body>
+<h1>Secret Scanner Report</h1>
+<p class="meta">Generated {{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</p>
+<table class="summary">
+<tr><th>Findings</th><td>{{.FindingCount}}</td></tr>
+{{range .SeverityCounts}}<tr><th><span class="severity severity-{{.Severity}}">{{upper .Severity}}</span></th><td>{{.Count}}</td></tr>
+{{end}}<tr><th>Ignored findings</th><td>{{.IgnoredFindings}}</td></tr>
+{{with .Stats}}<tr><th>Repositories</th><td>{{.Repositories}}</td></tr>
+<tr><th>Commits</th><td>{{.Commits}}</td></tr>
+<tr><th>Files</th><td>{{.Files}}</td></tr>
+<tr><th>Status</th><td>{{.Status}}</td></tr>
+{{end}}</table>
+{{if not .Repositories}}<p>No secrets found.</p>{{end}}
+{{range .Repositories}}
+<h2>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}} <small>({{.FindingCount}})</small></h2>
+{{range .Groups}}
+<details open>
+<summary><span class="severity severity-{{.Severity}}">{{upper .Severity}}</span> <strong>{{.Description}}</strong>{{if .SignatureID}} <code>{{.SignatureID}}</code>{{end}} ({{len .Findings}})</summary>
+{{if .Comment}}<p class="meta">{{.Comment}}</p>{{end}}
+{{range .Findings}}
+<div class="finding" id="{{.ID}}">
+<div><strong>{{if .FileURL}}<a href="{{.FileURL}}">{{.FilePath}}</a>{{else}}{{.FilePath}}{{end}}</strong>{{if .Line}}:{{.Line}}{{end}}</div>
+<div class="meta">
+{{if .CommitHash}}<span>Commit {{if .CommitURL}}<a href="{{.CommitURL}}">{{.CommitHash}}</a>{{else}}{{.CommitHash}}{{end}}</span>{{end}}
+{{if .CommitAuthor}}<span>{{.CommitAuthor}} &lt;{{.CommitAuthorEmail}}&gt;</span>{{end}}
+{{if .CommitDate}}<span>{{.CommitDate}}</span>{{end}}
+{{if .SecretMasked}}<span>Secret <code>{{.SecretMasked}}</code></span>{{end}}
+{{if .Verified}}<span>Verified {{.Verified}}</span>{{end}}
+<span>Confidence {{.Confidence}}</span>
+{{if .IsTestContext}}<span>Test context</span>{{end}}
+</div>
+{{if .ContextLines}}<pre>{{$finding := .}}{{range $i, $line := .ContextLines}}{{$number := lineNumber $finding.ContextStartLine $i}}<span class="line{{if eq $number $finding.Line}} match{{end}}"><span class="number">{{$number}}</span>{{highlight $line $finding.Secret}}</span>{{end}}</pre>
+{{else if .LineContent}}<pre><span class="line match">{{highlight .LineContent .Secret}}</span></pre>{{end}}
+</div>
+{{end}}
+</details>
+{{end}}
+{{end}}
+</body>
+</html>
+`
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package session
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grab/secret-scanner/scanner/findings"
+	"github.com/grab/secret-scanner/scanner/signatures"
+)
+
+func TestSession_ToHTMLReport(t *testing.T) {
+	sess := createNewSession()
+	sess.Findings = []*findings.Finding{
+		{RepositoryName: "web", SignatureID: "log-file", Description: "Log file", Severity: signatures.SeverityInfo, FilePath: "debug.log"},
+		{RepositoryName: "api", SignatureID: "slack-token", Description: "Slack token", Severity: signatures.SeverityHigh, FilePath: "b.yml", Line: 7},
+		{RepositoryName: "web", SignatureID: "aws-secret-access-key", Description: "AWS Secret Access Key", Severity: signatures.SeverityCritical, FilePath: "b.env", Line: 2},
+		{RepositoryName: "web", SignatureID: "aws-secret-access-key", Description: "AWS Secret Access Key", Severity: signatures.SeverityCritical, FilePath: "a.env", Line: 9},
+	}
+
+	report := sess.ToHTMLReport()
+	if len(report.Repositories) != 2 || report.Repositories[0].Name != "api" {
+		t.Errorf("Want repositories api, web, got %v", report.Repositories)
+		return
+	}
+	web := report.Repositories[1]
+	if web.FindingCount != 3 || len(web.Groups) != 2 {
+		t.Errorf("Want 3 findings in 2 groups, got %v in %v", web.FindingCount, len(web.Groups))
+		return
+	}
+	// groups are ordered by severity, findings by path
+	if web.Groups[0].SignatureID != "aws-secret-access-key" {
+		t.Errorf("Want aws-secret-access-key, got %v", web.Groups[0].SignatureID)
+	}
+	if web.Groups[0].Findings[0].FilePath != "a.env" {
+		t.Errorf("Want a.env, got %v", web.Groups[0].Findings[0].FilePath)
+	}
+	if len(report.SeverityCounts) != 3 || report.SeverityCounts[0].Severity != signatures.SeverityCritical || report.SeverityCounts[0].Count != 2 {
+		t.Errorf("Want 2 critical findings first, got %v", report.SeverityCounts)
+	}
+}
+
+func TestSession_ToHTML(t *testing.T) {
+	sess := createNewSession()
+	sess.Findings = []*findings.Finding{
+		{
+			RepositoryName:   "web",
+			RepositoryURL:    "https://github.com/grab/web",
+			Description:      "Slack token",
+			Severity:         signatures.SeverityHigh,
+			FilePath:         "config.js",
+			FileURL:          "https://github.com/grab/web/blob/master/config.js",
+			CommitURL:        "javascript:alert(1)",
+			CommitHash:       "abc123",
+			Line:             2,
+			Secret:           "xoxb-123",
+			ContextStartLine: 1,
+			ContextLines:     []string{"<script>", "token = 'xoxb-123'", "</script>"},
+		},
+	}
+
+	html, err := sess.ToHTML()
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	for _, want := range []string{
+		`<a href="https://github.com/grab/web/blob/master/config.js">config.js</a>`,
+		`<span class="line match"><span class="number">2</span>token = &#39;<mark>xoxb-123</mark>&#39;</span>`,
+		`&lt;script&gt;`,
+	} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("Want %v in report", want)
+		}
+	}
+	if strings.Contains(string(html), "<script>") || strings.Contains(string(html), "javascript:") {
+		t.Errorf("Want escaped content and URLs in report")
+	}
+}
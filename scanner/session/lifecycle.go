@@ -0,0 +1,199 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package session
+
+import (
+	"time"
+
+	"github.com/grab/secret-scanner/scanner/findings"
+	"github.com/grab/secret-scanner/scanner/state"
+)
+
+// trackedRepository holds what a scan saw of a repository, until its finding records are saved
+type trackedRepository struct {
+	// records of the reported findings by fingerprint
+	records map[string]*state.FindingRecord
+
+	// fingerprints of findings suppressed in this scan, e.g. by the ignore list or -min-severity
+	kept map[string]bool
+
+	// paths of files which were not matched, e.g. test contexts with -skip-tests
+	skippedPaths map[string]bool
+}
+
+// trackedRepository returns the tracking state of a repository, the caller must hold the lock
+func (s *Session) trackedRepository(repoID string) *trackedRepository {
+	if s.trackedFindings == nil {
+		s.trackedFindings = map[string]*trackedRepository{}
+	}
+	tracked := s.trackedFindings[repoID]
+	if tracked == nil {
+		tracked = &trackedRepository{
+			records:      map[string]*state.FindingRecord{},
+			kept:         map[string]bool{},
+			skippedPaths: map[string]bool{},
+		}
+		s.trackedFindings[repoID] = tracked
+	}
+	return tracked
+}
+
+// TrackFinding classifies a finding of a repository as new or existing by its record in the state store.
+// The updated record is kept until the repository is done and saved by SaveFindingRecords.
+func (s *Session) TrackFinding(repoID string, finding *findings.Finding) {
+	if !*s.Options.TrackFindings {
+		return
+	}
+
+	fingerprint := finding.TrackingFingerprint(repoID)
+
+	// the state store is read without holding the session lock, so that other workers do not wait for it
+	s.Lock()
+	_, seen := s.trackedRepository(repoID).records[fingerprint]
+	s.Unlock()
+	var stored *state.FindingRecord
+	if !seen {
+		stored = s.StateStore.GetFinding(*s.Options.GitProvider, repoID, fingerprint)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	tracked := s.trackedRepository(repoID)
+
+	// findings with the same fingerprint found again within the scan share the first classification
+	record, ok := tracked.records[fingerprint]
+	if !ok {
+		record = stored
+		scanTime := s.Stats.StartedAt.Format(time.RFC3339)
+		switch {
+		case record == nil:
+			record = &state.FindingRecord{
+				Fingerprint: fingerprint,
+				GitProvider: *s.Options.GitProvider,
+				RepoID:      repoID,
+				Status:      state.FindingStatusNew,
+				FirstSeen:   scanTime,
+			}
+		case record.Status == state.FindingStatusResolved:
+			// a resolved secret was added again
+			record.Status = state.FindingStatusNew
+			record.ResolvedAt = ""
+		default:
+			record.Status = state.FindingStatusExisting
+		}
+		record.FilePath = finding.FilePath
+		record.SignatureID = finding.SignatureID
+		record.Description = finding.Description
+		record.LastSeen = scanTime
+		tracked.records[fingerprint] = record
+	}
+
+	finding.Fingerprint = fingerprint
+	finding.Status = record.Status
+	finding.FirstSeen = record.FirstSeen
+}
+
+// KeepFinding marks a finding suppressed in this scan as still present,
+// its record is neither updated nor resolved
+func (s *Session) KeepFinding(repoID string, finding *findings.Finding) {
+	if !*s.Options.TrackFindings {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.trackedRepository(repoID).kept[finding.TrackingFingerprint(repoID)] = true
+}
+
+// SkipPath marks a file of a repository as not matched in this scan, the records of its findings are not resolved
+func (s *Session) SkipPath(repoID, filePath string) {
+	if !*s.Options.TrackFindings {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.trackedRepository(repoID).skippedPaths[filePath] = true
+}
+
+// SaveFindingRecords saves the records of the findings of a repository tracked in this scan.
+// If resolve is true, the repository was scanned completely at its latest revision,
+// and records not seen in this scan are resolved if their path was in scope and matched by an active signature.
+func (s *Session) SaveFindingRecords(repoID string, resolve bool, inScope func(filePath string) bool) error {
+	s.Lock()
+	tracked := s.trackedRepository(repoID)
+	delete(s.trackedFindings, repoID)
+	s.Unlock()
+
+	var records, resolved []*state.FindingRecord
+	for _, record := range tracked.records {
+		records = append(records, record)
+	}
+
+	if resolve {
+		stored, err := s.StateStore.ListFindings(*s.Options.GitProvider, repoID)
+		if err != nil {
+			return err
+		}
+		active := s.activeSignatures()
+		scanTime := s.Stats.StartedAt.Format(time.RFC3339)
+		for _, record := range stored {
+			_, seen := tracked.records[record.Fingerprint]
+			switch {
+			case seen, tracked.kept[record.Fingerprint], record.Status == state.FindingStatusResolved:
+			case tracked.skippedPaths[record.FilePath] || !inScope(record.FilePath):
+			case !active[record.SignatureID] && !active[record.Description]:
+			default:
+				record.Status = state.FindingStatusResolved
+				record.ResolvedAt = scanTime
+				records = append(records, record)
+				resolved = append(resolved, record)
+			}
+		}
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+	err := s.StateStore.SaveFindings(records)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.ResolvedFindings = append(s.ResolvedFindings, resolved...)
+	return nil
+}
+
+// activeSignatures returns the IDs, or descriptions of signatures without ID, of the signatures matched in this scan
+func (s *Session) activeSignatures() map[string]bool {
+	active := map[string]bool{}
+	for _, sig := range s.Signatures {
+		if sig.ID() != "" {
+			active[sig.ID()] = true
+		} else {
+			active[sig.Description()] = true
+		}
+	}
+	return active
+}
+
+// CountFindingStatuses returns the number of new, existing and resolved findings of the session
+func (s *Session) CountFindingStatuses() (newCount, existingCount, resolvedCount int) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, finding := range s.Findings {
+		switch finding.Status {
+		case state.FindingStatusNew:
+			newCount++
+		case state.FindingStatusExisting:
+			existingCount++
+		}
+	}
+	return newCount, existingCount, len(s.ResolvedFindings)
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package session
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grab/secret-scanner/scanner/findings"
+	"github.com/grab/secret-scanner/scanner/state"
+)
+
+func TestSession_TrackFinding(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Errorf("Cannot create temp. dir.: %v", err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	storePath := path.Join(tempDir, "state.json")
+	opt := defaultOptions
+	opt.StateStore = &storePath
+	gitProvider := "github"
+	opt.GitProvider = &gitProvider
+	trackFindings := true
+	opt.TrackFindings = &trackFindings
+
+	inScope := func(p string) bool { return !strings.HasPrefix(p, "vendor/") }
+	firstScan := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// newFinding creates a finding of a secret in a file, the line is neither part of the ID nor of the fingerprint
+	newFinding := func(name string, line uint64) *findings.Finding {
+		finding := &findings.Finding{FilePath: name + ".txt", SignatureID: "slack-token", Line: line}
+		finding.SetSecret("secret-"+name, "salt")
+		finding.ID, _ = finding.GenerateHashID()
+		return finding
+	}
+	fingerprint := func(name string) string {
+		return newFinding(name, 1).TrackingFingerprint("repo-1")
+	}
+
+	// scanWith runs the session of a full scan finding the secrets of the names,
+	// secrets are moved down a line in every scan
+	line := uint64(0)
+	scanWith := func(startedAt time.Time, suppress func(sess *Session), names ...string) *Session {
+		sess := createNewSession()
+		sess.Initialize(opt)
+		sess.Stats.StartedAt = startedAt
+		line++
+		for _, name := range names {
+			finding := newFinding(name, line)
+			sess.TrackFinding("repo-1", finding)
+			sess.AddFinding(finding)
+		}
+		if suppress != nil {
+			suppress(sess)
+		}
+		err := sess.SaveFindingRecords("repo-1", true, inScope)
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+		}
+		sess.End()
+		sess.Close()
+		return sess
+	}
+	scan := func(startedAt time.Time, names ...string) *Session {
+		return scanWith(startedAt, nil, names...)
+	}
+
+	sess := scan(firstScan, "a", "b")
+	salt := sess.SecretSalt
+	if newCount, existingCount, resolvedCount := sess.CountFindingStatuses(); newCount != 2 || existingCount != 0 || resolvedCount != 0 {
+		t.Errorf("Want 2 new findings, got %v new, %v existing and %v resolved", newCount, existingCount, resolvedCount)
+	}
+
+	// a is still present, b is removed
+	sess = scan(firstScan.Add(time.Hour), "a")
+	if sess.SecretSalt != salt {
+		t.Errorf("Want the salt of the first scan, got %v", sess.SecretSalt)
+	}
+	if sess.Findings[0].Status != state.FindingStatusExisting {
+		t.Errorf("Want %v, got %v", state.FindingStatusExisting, sess.Findings[0].Status)
+	}
+	if sess.Findings[0].FirstSeen != firstScan.Format(time.RFC3339) {
+		t.Errorf("Want %v, got %v", firstScan.Format(time.RFC3339), sess.Findings[0].FirstSeen)
+	}
+	if len(sess.ResolvedFindings) != 1 || sess.ResolvedFindings[0].Fingerprint != fingerprint("b") {
+		t.Errorf("Want b resolved, got %v", sess.ResolvedFindings)
+	}
+
+	// b is added again
+	sess = scan(firstScan.Add(2*time.Hour), "a", "b")
+	if sess.Findings[1].Status != state.FindingStatusNew {
+		t.Errorf("Want %v, got %v", state.FindingStatusNew, sess.Findings[1].Status)
+	}
+	if len(sess.ResolvedFindings) != 0 {
+		t.Errorf("Want no resolved findings, got %v", sess.ResolvedFindings)
+	}
+
+	// findings out of the scanned paths, of skipped files or of signatures not matched in the scan are not resolved
+	store, err := state.NewStore(storePath)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	err = store.SaveFindings([]*state.FindingRecord{
+		{Fingerprint: "c", GitProvider: gitProvider, RepoID: "repo-1", FilePath: "vendor/c.txt", SignatureID: "slack-token", Status: state.FindingStatusNew},
+		{Fingerprint: "d", GitProvider: gitProvider, RepoID: "repo-1", FilePath: "d_test.txt", SignatureID: "slack-token", Status: state.FindingStatusNew},
+		{Fingerprint: "e", GitProvider: gitProvider, RepoID: "repo-1", FilePath: "e.txt", SignatureID: "disabled-rule", Status: state.FindingStatusNew},
+	})
+	store.Close()
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	sess = scanWith(firstScan.Add(3*time.Hour), func(sess *Session) {
+		sess.SkipPath("repo-1", "d_test.txt")
+	}, "a")
+	if len(sess.ResolvedFindings) != 1 || sess.ResolvedFindings[0].Fingerprint != fingerprint("b") {
+		t.Errorf("Want b resolved, got %v", sess.ResolvedFindings)
+	}
+
+	// suppressed findings, e.g. by the ignore list, are not resolved
+	sess = scanWith(firstScan.Add(4*time.Hour), func(sess *Session) {
+		sess.KeepFinding("repo-1", newFinding("a", 1))
+		sess.SkipPath("repo-1", "d_test.txt")
+	})
+	if len(sess.ResolvedFindings) != 0 {
+		t.Errorf("Want no resolved findings, got %v", sess.ResolvedFindings)
+	}
+
+	store, err = state.NewStore(storePath)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	defer store.Close()
+	record := store.GetFinding(gitProvider, "repo-1", fingerprint("b"))
+	if record == nil || record.Status != state.FindingStatusResolved || record.ResolvedAt != firstScan.Add(3*time.Hour).Format(time.RFC3339) {
+		t.Errorf("Want b resolved, got %v", record)
+	}
+	record = store.GetFinding(gitProvider, "repo-1", fingerprint("a"))
+	if record == nil || record.FirstSeen != firstScan.Format(time.RFC3339) || record.LastSeen != firstScan.Add(3*time.Hour).Format(time.RFC3339) {
+		t.Errorf("Want a first seen at the first scan, got %v", record)
+	}
+}
+
+func TestSession_TrackFinding_Concurrently(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Errorf("Cannot create temp. dir.: %v", err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	storePath := path.Join(tempDir, "state.json")
+	opt := defaultOptions
+	opt.StateStore = &storePath
+	trackFindings := true
+	opt.TrackFindings = &trackFindings
+
+	sess := createNewSession()
+	sess.Initialize(opt)
+	defer sess.Close()
+	defer sess.End()
+
+	// the state store is read outside of the session lock, the first classification still applies to all
+	var wg sync.WaitGroup
+	tracked := make([]*findings.Finding, 20)
+	for i := range tracked {
+		tracked[i] = &findings.Finding{FilePath: "a.txt", SignatureID: "slack-token", Line: uint64(i)}
+		tracked[i].SetSecret("secret-a", sess.SecretSalt)
+		wg.Add(1)
+		go func(finding *findings.Finding) {
+			defer wg.Done()
+			sess.TrackFinding("repo-1", finding)
+		}(tracked[i])
+	}
+	wg.Wait()
+
+	for _, finding := range tracked {
+		if finding.Status != state.FindingStatusNew || finding.Fingerprint != tracked[0].Fingerprint {
+			t.Errorf("Want %v finding %v, got %v finding %v", state.FindingStatusNew, tracked[0].Fingerprint, finding.Status, finding.Fingerprint)
+		}
+	}
+	if len(sess.trackedFindings["repo-1"].records) != 1 {
+		t.Errorf("Want 1 record, got %v", len(sess.trackedFindings["repo-1"].records))
+	}
+}
@@ -0,0 +1,268 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/grab/secret-scanner/scanner/findings"
+	"github.com/grab/secret-scanner/scanner/signatures"
+	"github.com/grab/secret-scanner/scanner/state"
+)
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// sarifSecuritySeverities maps severities to CVSS-like scores
+var sarifSecuritySeverities = map[string]string{
+	signatures.SeverityCritical: "9.5",
+	signatures.SeverityHigh:     "8.0",
+	signatures.SeverityMedium:   "5.5",
+	signatures.SeverityLow:      "2.0",
+	signatures.SeverityInfo:     "0.0",
+}
+
+// SARIFLog is the root object of a SARIF 2.1.0 log file
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun contains the results of a single tool run
+type SARIFRun struct {
+	Tool       SARIFTool     `json:"tool"`
+	ColumnKind string        `json:"columnKind"`
+	Results    []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the tool producing the results
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver describes the tool component and its rules
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes a signature
+type SARIFRule struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name,omitempty"`
+	ShortDescription SARIFMessage           `json:"shortDescription"`
+	FullDescription  *SARIFMessage          `json:"fullDescription,omitempty"`
+	DefaultConfig    SARIFRuleConfig        `json:"defaultConfiguration"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+// SARIFRuleConfig contains the default configuration of a rule
+type SARIFRuleConfig struct {
+	Level string `json:"level"`
+}
+
+// SARIFMessage is a plain text message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult describes a finding
+type SARIFResult struct {
+	RuleID              string                 `json:"ruleId"`
+	RuleIndex           int                    `json:"ruleIndex"`
+	Level               string                 `json:"level"`
+	Message             SARIFMessage           `json:"message"`
+	Locations           []SARIFLocation        `json:"locations"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints"`
+	BaselineState       string                 `json:"baselineState,omitempty"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+// SARIFLocation describes where a finding is located
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation contains the file and region of a finding
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation contains the file path of a finding
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion contains the line and column of a finding, both starting from 1
+type SARIFRegion struct {
+	StartLine   uint64 `json:"startLine"`
+	StartColumn uint64 `json:"startColumn,omitempty"`
+}
+
+// ToSARIF converts scan results to a SARIF log
+func (s *Session) ToSARIF() *SARIFLog {
+	driver := SARIFDriver{
+		Name:           SARIFToolName,
+		InformationURI: SARIFToolInformationURI,
+		Rules:          []SARIFRule{},
+	}
+	ruleIndexes := map[string]int{}
+	addRule := func(rule SARIFRule) int {
+		if i, ok := ruleIndexes[rule.ID]; ok {
+			return i
+		}
+		driver.Rules = append(driver.Rules, rule)
+		ruleIndexes[rule.ID] = len(driver.Rules) - 1
+		return ruleIndexes[rule.ID]
+	}
+
+	for _, sig := range s.Signatures {
+		addRule(newSARIFRule(SARIFRuleID(sig.ID(), sig.Description()), sig.Description(), sig.Comment(), sig.Part(), sig.Severity(), sig.Tags()))
+	}
+
+	results := []SARIFResult{}
+	for _, finding := range s.Findings {
+		ruleID := SARIFRuleID(finding.SignatureID, finding.Description)
+		ruleIndex := addRule(newSARIFRule(ruleID, finding.Description, finding.Comment, "", finding.Severity, finding.Tags))
+		results = append(results, newSARIFResult(finding, ruleID, ruleIndex))
+	}
+
+	return &SARIFLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs: []SARIFRun{
+			{
+				Tool:       SARIFTool{Driver: driver},
+				ColumnKind: SARIFColumnKind,
+				Results:    results,
+			},
+		},
+	}
+}
+
+// SaveSARIFToFile exports scan results to file in SARIF format
+func (s *Session) SaveSARIFToFile(location string) (string, error) {
+	sarifJSON, err := json.MarshalIndent(s.ToSARIF(), "", "\t")
+	if err != nil {
+		return "", err
+	}
+	return writeReportFile(location, sarifJSON)
+}
+
+// SARIFRuleID returns the signature ID, or a slug of the description for signatures without ID
+func SARIFRuleID(id, description string) string {
+	if id != "" {
+		return id
+	}
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(description), "-"), "-")
+	if slug == "" {
+		return "unknown"
+	}
+	return slug
+}
+
+// SARIFLevel maps a severity to a SARIF level
+func SARIFLevel(severity string) string {
+	switch severity {
+	case signatures.SeverityCritical, signatures.SeverityHigh:
+		return SARIFLevelError
+	case signatures.SeverityMedium:
+		return SARIFLevelWarning
+	case signatures.SeverityLow, signatures.SeverityInfo:
+		return SARIFLevelNote
+	default:
+		return SARIFLevelError
+	}
+}
+
+// SARIFBaselineState maps a finding lifecycle status to a SARIF baseline state,
+// findings of untracked scans have none
+func SARIFBaselineState(status string) string {
+	switch status {
+	case state.FindingStatusNew:
+		return SARIFBaselineStateNew
+	case state.FindingStatusExisting:
+		return SARIFBaselineStateUnchanged
+	default:
+		return ""
+	}
+}
+
+func newSARIFRule(id, description, comment, part, severity string, tags []string) SARIFRule {
+	rule := SARIFRule{
+		ID:               id,
+		Name:             description,
+		ShortDescription: SARIFMessage{Text: description},
+		DefaultConfig:    SARIFRuleConfig{Level: SARIFLevel(severity)},
+		Properties:       map[string]interface{}{},
+	}
+	if comment != "" {
+		rule.FullDescription = &SARIFMessage{Text: comment}
+	}
+	if part != "" {
+		rule.Properties["part"] = part
+	}
+	if len(tags) > 0 {
+		rule.Properties["tags"] = tags
+	}
+	// used by code scanning tools to rank security results
+	if score, ok := sarifSecuritySeverities[severity]; ok {
+		rule.Properties["security-severity"] = score
+	}
+	return rule
+}
+
+func newSARIFResult(finding *findings.Finding, ruleID string, ruleIndex int) SARIFResult {
+	message := finding.Description
+	if finding.Comment != "" {
+		message = message + ": " + finding.Comment
+	}
+
+	location := SARIFLocation{
+		PhysicalLocation: SARIFPhysicalLocation{
+			ArtifactLocation: SARIFArtifactLocation{URI: finding.FilePath},
+		},
+	}
+	// SARIF lines start from 1, path findings have no line
+	if finding.Line > 0 {
+		location.PhysicalLocation.Region = &SARIFRegion{StartLine: finding.Line, StartColumn: finding.Column}
+	}
+
+	properties := map[string]interface{}{}
+	for key, value := range map[string]string{
+		"repositoryOwner": finding.RepositoryOwner,
+		"repositoryName":  finding.RepositoryName,
+		"repositoryUrl":   finding.RepositoryURL,
+		"commitHash":      finding.CommitHash,
+		"commitUrl":       finding.CommitURL,
+		"fileUrl":         finding.FileURL,
+		"verified":        finding.Verified,
+	} {
+		if value != "" {
+			properties[key] = value
+		}
+	}
+
+	fingerprints := map[string]string{SARIFFingerprintKey: finding.ID}
+	if finding.SecretHash != "" {
+		fingerprints[SARIFSecretFingerprintKey] = finding.SecretHash
+	}
+
+	return SARIFResult{
+		RuleID:              ruleID,
+		RuleIndex:           ruleIndex,
+		Level:               SARIFLevel(finding.Severity),
+		Message:             SARIFMessage{Text: message},
+		Locations:           []SARIFLocation{location},
+		PartialFingerprints: fingerprints,
+		BaselineState:       SARIFBaselineState(finding.Status),
+		Properties:          properties,
+	}
+}
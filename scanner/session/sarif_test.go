@@ -0,0 +1,167 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/grab/secret-scanner/scanner/findings"
+	"github.com/grab/secret-scanner/scanner/signatures"
+	"github.com/grab/secret-scanner/scanner/state"
+)
+
+func TestSession_ToSARIF(t *testing.T) {
+	sess := createNewSession()
+	sess.Signatures = signatures.LoadSignatures()
+	sess.Findings = []*findings.Finding{
+		{ID: "abc123", FilePath: "config/settings.yml", Description: "AWS Access Key ID", Line: 12, Severity: signatures.SeverityCritical},
+		{ID: "def456", FilePath: "certs/server.pem", Description: "Potential cryptographic private key", Severity: signatures.SeverityMedium},
+		{ID: "ghi789", FilePath: "main.go", Description: "Custom token", SignatureID: "custom-token", Line: 3},
+	}
+
+	sarif := sess.ToSARIF()
+	if sarif.Version != SARIFVersion {
+		t.Errorf("Want %v, got %v", SARIFVersion, sarif.Version)
+	}
+	if len(sarif.Runs) != 1 {
+		t.Errorf("Want 1, got %v", len(sarif.Runs))
+		return
+	}
+
+	run := sarif.Runs[0]
+	if run.ColumnKind != SARIFColumnKind {
+		t.Errorf("Want %v, got %v", SARIFColumnKind, run.ColumnKind)
+	}
+	if len(run.Results) != 3 {
+		t.Errorf("Want 3, got %v", len(run.Results))
+		return
+	}
+	ruleIDs := map[string]bool{}
+	for _, rule := range run.Tool.Driver.Rules {
+		if ruleIDs[rule.ID] {
+			t.Errorf("Want unique rule IDs, got duplicate %v", rule.ID)
+		}
+		ruleIDs[rule.ID] = true
+	}
+
+	for i, result := range run.Results {
+		rule := run.Tool.Driver.Rules[result.RuleIndex]
+		if rule.ID != result.RuleID {
+			t.Errorf("Want %v, got %v", result.RuleID, rule.ID)
+		}
+		if result.PartialFingerprints[SARIFFingerprintKey] != sess.Findings[i].ID {
+			t.Errorf("Want %v, got %v", sess.Findings[i].ID, result.PartialFingerprints[SARIFFingerprintKey])
+		}
+		if result.Locations[0].PhysicalLocation.ArtifactLocation.URI != sess.Findings[i].FilePath {
+			t.Errorf("Want %v, got %v", sess.Findings[i].FilePath, result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		}
+	}
+
+	if run.Results[0].RuleID != "aws-access-key-id" {
+		t.Errorf("Want aws-access-key-id, got %v", run.Results[0].RuleID)
+	}
+	if run.Results[0].Locations[0].PhysicalLocation.Region.StartLine != 12 {
+		t.Errorf("Want 12, got %v", run.Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	}
+	if run.Results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("Want no region, got %v", run.Results[1].Locations[0].PhysicalLocation.Region)
+	}
+	if run.Results[0].Level != SARIFLevelError || run.Results[1].Level != SARIFLevelWarning {
+		t.Errorf("Want error and warning, got %v and %v", run.Results[0].Level, run.Results[1].Level)
+	}
+	if run.Results[2].RuleID != "custom-token" {
+		t.Errorf("Want custom-token, got %v", run.Results[2].RuleID)
+	}
+}
+
+func TestSession_ToSARIF_NonASCIIColumn(t *testing.T) {
+	// U+023A and the Kelvin sign change their length when lowercased, columns count code points
+	content := "\u023a\u212a\n// Größe 😀 sauce_key = '0123abcd-4567-89ef-0123-456789abcdef'\n"
+	matchFile := signatures.NewMatchFile("config.go", content)
+
+	sess := createNewSession()
+	sess.Signatures = signatures.LoadSignatures()
+	for _, sig := range sess.Signatures {
+		if sig.ID() != "sauce-token" {
+			continue
+		}
+		for _, match := range sig.Match(matchFile) {
+			sess.Findings = append(sess.Findings, &findings.Finding{ID: "abc123", FilePath: "config.go", SignatureID: sig.ID(), Line: match.Line, Column: match.Column})
+		}
+	}
+
+	run := sess.ToSARIF().Runs[0]
+	if len(run.Results) != 1 {
+		t.Errorf("Want 1, got %v", len(run.Results))
+		return
+	}
+	region := run.Results[0].Locations[0].PhysicalLocation.Region
+	if region.StartLine != 2 || region.StartColumn != 12 {
+		t.Errorf("Want 2:12, got %v:%v", region.StartLine, region.StartColumn)
+	}
+}
+
+func TestSARIFBaselineState(t *testing.T) {
+	cases := map[string]string{
+		state.FindingStatusNew:      SARIFBaselineStateNew,
+		state.FindingStatusExisting: SARIFBaselineStateUnchanged,
+		"":                          "",
+	}
+	for status, want := range cases {
+		if got := SARIFBaselineState(status); got != want {
+			t.Errorf("Want %v for %q, got %v", want, status, got)
+		}
+	}
+}
+
+func TestSession_SaveReportToFile(t *testing.T) {
+	sess := createNewSession()
+	sess.Initialize(defaultOptions)
+	defer sess.End()
+
+	tempDir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Errorf("Cannot create temp. dir.: %v", err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	format := FormatSARIF
+	sess.Options.Format = &format
+	filepath := path.Join(tempDir, "ss-test.sarif")
+	_, err = sess.SaveReportToFile(filepath)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+
+	data, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	sarif := &SARIFLog{}
+	err = json.Unmarshal(data, sarif)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if sarif.Runs[0].Tool.Driver.Name != SARIFToolName {
+		t.Errorf("Want %v, got %v", SARIFToolName, sarif.Runs[0].Tool.Driver.Name)
+	}
+
+	format = "xml"
+	_, err = sess.SaveReportToFile(path.Join(tempDir, "ss-test.xml"))
+	if err == nil {
+		t.Errorf("Want err, got no err")
+	}
+}
@@ -7,74 +7,279 @@ package session
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/grab/secret-scanner/scanner/blobcache"
 	"github.com/grab/secret-scanner/scanner/state"
+	"github.com/grab/secret-scanner/scanner/verify"
 
 	"github.com/grab/secret-scanner/scanner/findings"
 
 	"github.com/grab/secret-scanner/common/filehandler"
 	"github.com/grab/secret-scanner/common/log"
 	"github.com/grab/secret-scanner/scanner/gitprovider"
+	"github.com/grab/secret-scanner/scanner/ignore"
 	"github.com/grab/secret-scanner/scanner/options"
 	"github.com/grab/secret-scanner/scanner/signatures"
 	"github.com/grab/secret-scanner/scanner/stats"
 )
 
+// ErrFindingNotFound is returned if no finding of the session has the given ID
+var ErrFindingNotFound = errors.New("finding not found")
+
 // Session contains fields describing a scan session
 type Session struct {
 	sync.Mutex
 
-	Options      options.Options `json:"-"`
-	Out          *log.Logger     `json:"-"`
-	Stats        *stats.Stats
-	Findings     []*findings.Finding
-	Repositories []*gitprovider.Repository
-	Signatures   []signatures.Signature `json:"-"`
-	StateStore   *state.JSONFileStore
+	Options          options.Options `json:"-"`
+	Out              *log.Logger     `json:"-"`
+	Stats            *stats.Stats
+	Findings         []*findings.Finding
+	IgnoredFindings  []*findings.Finding
+	ResolvedFindings []*state.FindingRecord
+	Repositories     []*gitprovider.Repository
+	Signatures       []signatures.Signature `json:"-"`
+	Prefilter        *signatures.Prefilter  `json:"-"`
+	SecretSalt       string                 `json:"-"`
+	IgnoreList       *ignore.List           `json:"-"`
+	IgnoreFilePath   string                 `json:"-"`
+	StateStore       state.Store            `json:"-"`
+	JSONLFile        *os.File               `json:"-"`
+	BlobCache        *blobcache.Cache       `json:"-"`
+	Verifier         *verify.Registry       `json:"-"`
+
+	// findings of the repositories being scanned by repository ID
+	trackedFindings map[string]*trackedRepository
 }
 
 // Initialize inits a scan session
 func (s *Session) Initialize(options options.Options) {
 	s.Options = options
-	s.InitStateStoreOrFail("")
+	s.InitStateStoreOrFail(*s.Options.StateStore)
 	s.InitLogger()
 	s.InitStats()
 	s.InitThreads()
-	s.Signatures = signatures.LoadSignatures()
+	s.InitSeverityOrFail()
+	s.InitSecretSaltOrFail()
+	s.InitSignaturesOrFail()
+	s.InitPrefilter()
+	s.InitBlobCache()
+	s.InitVerifier()
+	s.InitIgnoreListOrFail()
+	s.InitJSONLOrFail()
+	s.InitLoadOrFail()
 }
 
 // End end a scan session
 func (s *Session) End() {
 	s.Stats.FinishedAt = time.Now()
 	s.Stats.Status = StatusFinished
+	if *s.Options.BlobCache && s.BlobCache != nil {
+		err := s.StateStore.SaveBlobCache(s.BlobCache.Version(), s.BlobCache.Hashes())
+		if err != nil {
+			s.Out.Error("Unable to save blob cache: %v\n", err)
+		}
+	}
+	if s.JSONLFile != nil {
+		_ = s.JSONLFile.Close()
+		s.JSONLFile = nil
+	}
+	// the UI keeps serving the state store after the scan, it is closed with Close
+	if !*s.Options.UI {
+		s.Close()
+	}
+}
+
+// Close releases the state store and the JSON Lines file of a session
+func (s *Session) Close() {
 	s.StateStore.Close()
+	if s.JSONLFile != nil {
+		_ = s.JSONLFile.Close()
+		s.JSONLFile = nil
+	}
+}
+
+// InitStateStoreOrFail inits a history storage from a store URI
+func (s *Session) InitStateStoreOrFail(uri string) {
+	store, err := state.NewStore(uri)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("Unable to initialize StateStore: %v", err))
+		os.Exit(1)
+	}
+	s.StateStore = store
+}
+
+// InitSeverityOrFail validates the severity options
+func (s *Session) InitSeverityOrFail() {
+	if *s.Options.MinSeverity == "" {
+		*s.Options.MinSeverity = signatures.SeverityInfo
+	}
+	for name, severity := range map[string]string{"min-severity": *s.Options.MinSeverity, "fail-on": *s.Options.FailOn} {
+		if severity != "" && !signatures.IsValidSeverity(severity) {
+			fmt.Println(fmt.Sprintf("Invalid %s %q (Currently supports %s)", name, severity, strings.Join(signatures.Severities, ", ")))
+			os.Exit(1)
+		}
+	}
+}
+
+// InitSecretSaltOrFail sets the salt of the secret hashes,
+// a random salt is generated and kept in the state store if none is configured
+func (s *Session) InitSecretSaltOrFail() {
+	s.SecretSalt = *s.Options.SecretSalt
+	if s.SecretSalt == "" {
+		s.SecretSalt = os.Getenv(SecretSaltEnv)
+	}
+	if s.SecretSalt != "" {
+		return
+	}
+
+	// finding IDs and fingerprints are derived from the secret hash, which must not change across scans
+	salt, err := s.StateStore.LoadSecretSalt()
+	if err != nil {
+		fmt.Println(fmt.Sprintf("Unable to load secret salt: %v", err))
+		os.Exit(1)
+	}
+	if salt != "" {
+		s.SecretSalt = salt
+		return
+	}
+
+	randomSalt := make([]byte, 32)
+	_, err = rand.Read(randomSalt)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("Unable to generate secret salt: %v", err))
+		os.Exit(1)
+	}
+	s.SecretSalt = hex.EncodeToString(randomSalt)
+
+	err = s.StateStore.SaveSecretSalt(s.SecretSalt)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("Unable to save secret salt: %v", err))
+		os.Exit(1)
+	}
 }
 
-// InitStateStoreOrFail inits a history storage
-func (s *Session) InitStateStoreOrFail(filepath string) {
-	s.StateStore = &state.JSONFileStore{}
+// InitSignaturesOrFail loads built-in signatures and custom rule files, and selects the enabled ones
+func (s *Session) InitSignaturesOrFail() {
+	s.Signatures = signatures.LoadSignatures()
+	if *s.Options.Entropy {
+		s.Signatures = append(s.Signatures, signatures.NewEntropySignature(*s.Options.EntropyBase64, *s.Options.EntropyHex, *s.Options.EntropyMinLength))
+	}
+
+	if *s.Options.Rules != "" {
+		customSigs, err := signatures.LoadRuleFiles(s.Options.ParseRuleFiles())
+		if err != nil {
+			fmt.Println(fmt.Sprintf("Unable to load rule files: %v", err))
+			os.Exit(1)
+		}
+
+		if *s.Options.RulesReplace {
+			s.Signatures = customSigs
+		} else {
+			s.Signatures = signatures.MergeSignatures(s.Signatures, customSigs)
+		}
+	}
+
+	sigs, err := signatures.SelectSignatures(s.Signatures, s.Options.ParseEnableRules(), s.Options.ParseDisableRules(), s.Options.ParseTags())
+	if err != nil {
+		fmt.Println(fmt.Sprintf("Unable to select rules: %v", err))
+		os.Exit(1)
+	}
+	s.Signatures = sigs
+}
+
+// InitPrefilter inits the keyword prefilter of the loaded signatures
+func (s *Session) InitPrefilter() {
+	s.Prefilter = signatures.NewPrefilter(s.Signatures)
+}
+
+// InitBlobCache inits the cache of content without signature matches if enabled,
+// and loads the persisted cache of the current signature set
+func (s *Session) InitBlobCache() {
+	if !*s.Options.BlobCache {
+		s.BlobCache = nil
+		return
+	}
+	s.BlobCache = blobcache.New(signatures.Version(s.Signatures))
+
+	hashes, err := s.StateStore.LoadBlobCache(s.BlobCache.Version())
+	if err != nil {
+		s.Out.Error("Unable to load blob cache: %v\n", err)
+		return
+	}
+	s.BlobCache.Load(hashes)
+}
+
+// InitVerifier inits the verifiers of found secrets if verification is enabled
+func (s *Session) InitVerifier() {
+	if !*s.Options.Verify {
+		return
+	}
+	s.Verifier = verify.NewRegistry(verify.DefaultConfig())
+}
 
+// InitIgnoreListOrFail loads the global ignore file
+func (s *Session) InitIgnoreListOrFail() {
+	filepath := *s.Options.IgnoreFile
 	if filepath == "" {
-		defaultPath, err := s.StateStore.GetDefaultStorePath()
+		defaultPath, err := ignore.GetDefaultGlobalPath()
 		if err != nil {
-			fmt.Println(fmt.Sprintf("Unable to create default history file path: %v", err))
+			fmt.Println(fmt.Sprintf("Unable to get default ignore file path: %v", err))
 			os.Exit(1)
 		}
 		filepath = defaultPath
 	}
 
-	err := s.StateStore.Initialize(filepath)
+	ignoreList, err := ignore.Load(filepath)
 	if err != nil {
-		fmt.Println(fmt.Sprintf("Unable to initialize StateStore: %v", err))
+		fmt.Println(fmt.Sprintf("Unable to load ignore file: %v", err))
+		os.Exit(1)
+	}
+	s.IgnoreList = ignoreList
+	s.IgnoreFilePath = filepath
+}
+
+// InitJSONLOrFail opens the JSON Lines file findings are appended to
+func (s *Session) InitJSONLOrFail() {
+	if *s.Options.JSONL == "" {
+		return
+	}
+
+	err := os.MkdirAll(path.Dir(*s.Options.JSONL), 0700)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("Unable to create JSON Lines file dir: %v", err))
+		os.Exit(1)
+	}
+
+	file, err := os.OpenFile(*s.Options.JSONL, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("Unable to open JSON Lines file: %v", err))
+		os.Exit(1)
+	}
+	s.JSONLFile = file
+}
+
+// InitLoadOrFail restores the session saved in the -load file
+func (s *Session) InitLoadOrFail() {
+	if *s.Options.Load == "" {
+		return
+	}
+
+	err := s.LoadFromFile(*s.Options.Load)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("Unable to load session file: %v", err))
 		os.Exit(1)
 	}
 }
@@ -114,16 +319,128 @@ func (s *Session) AddFinding(finding *findings.Finding) {
 	s.Lock()
 	defer s.Unlock()
 	s.Findings = append(s.Findings, finding)
+	s.appendJSONL(finding)
 }
 
-// SaveToFile exports scan results to file
-func (s *Session) SaveToFile(location string) (string, error) {
-	// get absolute path
-	absPath, err := filepath.Abs(location)
+// appendJSONL writes a finding as a single line to the JSON Lines file, the caller must hold the lock
+func (s *Session) appendJSONL(finding *findings.Finding) {
+	if s.JSONLFile == nil {
+		return
+	}
+
+	findingJSON, err := json.Marshal(finding)
 	if err != nil {
-		return "", err
+		s.Out.Error("Unable to marshal finding %s: %s\n", finding.ID, err)
+		return
+	}
+
+	// the file is unbuffered, each line is written with a single write call
+	_, err = s.JSONLFile.Write(append(findingJSON, '\n'))
+	if err != nil {
+		s.Out.Error("Unable to write finding %s to %s: %s\n", finding.ID, s.JSONLFile.Name(), err)
+	}
+}
+
+// MarkFalsePositive moves the findings with an ID to the ignored findings,
+// and appends their fingerprint to the global ignore file so they are suppressed in later scans
+func (s *Session) MarkFalsePositive(id string) ([]*findings.Finding, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var marked, remaining []*findings.Finding
+	for _, finding := range s.Findings {
+		if finding.ID == id {
+			marked = append(marked, finding)
+		} else {
+			remaining = append(remaining, finding)
+		}
+	}
+	if len(marked) == 0 {
+		return nil, ErrFindingNotFound
+	}
+
+	rule := ignore.PrefixFingerprint + id
+	comment := fmt.Sprintf("%s in %s, marked as false positive on %s", marked[0].Description, marked[0].FilePath, time.Now().Format(time.RFC3339))
+	err := ignore.AppendRule(s.IgnoreFilePath, rule, comment)
+	if err != nil {
+		return nil, err
+	}
+	if s.IgnoreList == nil {
+		s.IgnoreList = ignore.NewList()
+	}
+	s.IgnoreList.Fingerprints[id] = true
+
+	for _, finding := range marked {
+		finding.IgnoreReason = fmt.Sprintf("%s %s", ignore.DefaultFilename, rule)
+	}
+	s.Findings = remaining
+	s.IgnoredFindings = append(s.IgnoredFindings, marked...)
+	return marked, nil
+}
+
+// FailOnFindings checks if a finding has at least the -fail-on severity,
+// findings already seen in an earlier scan are not considered
+func (s *Session) FailOnFindings() bool {
+	if *s.Options.FailOn == "" {
+		return false
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	failOn := signatures.SeverityRank(*s.Options.FailOn)
+	for _, finding := range s.Findings {
+		if finding.Status != state.FindingStatusExisting && signatures.SeverityRank(finding.Severity) >= failOn {
+			return true
+		}
 	}
+	return false
+}
 
+// AddIgnoredFinding adds a finding suppressed by an inline comment or ignore rule
+func (s *Session) AddIgnoredFinding(finding *findings.Finding) {
+	s.Lock()
+	defer s.Unlock()
+	s.IgnoredFindings = append(s.IgnoredFindings, finding)
+}
+
+// savedSession holds the fields of a session restored from a JSON report
+type savedSession struct {
+	Stats            *stats.Stats
+	Findings         []*findings.Finding
+	IgnoredFindings  []*findings.Finding
+	ResolvedFindings []*state.FindingRecord
+	Repositories     []*gitprovider.Repository
+}
+
+// LoadFromFile restores the stats, findings and repositories of a session saved as JSON report by SaveToFile.
+// Loaded findings are appended to the JSON Lines file.
+func (s *Session) LoadFromFile(location string) error {
+	data, err := ioutil.ReadFile(location)
+	if err != nil {
+		return err
+	}
+
+	saved := &savedSession{}
+	err = json.Unmarshal(data, saved)
+	if err != nil || saved.Stats == nil {
+		return fmt.Errorf("session file %s is corrupt or not a JSON report", location)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.Stats = saved.Stats
+	s.Findings = saved.Findings
+	s.IgnoredFindings = saved.IgnoredFindings
+	s.ResolvedFindings = saved.ResolvedFindings
+	s.Repositories = saved.Repositories
+	for _, finding := range s.Findings {
+		s.appendJSONL(finding)
+	}
+	return nil
+}
+
+// SaveToFile exports scan results to file
+func (s *Session) SaveToFile(location string) (string, error) {
 	// session to json bytes
 	sessionJSON, err := json.Marshal(s)
 	if err != nil {
@@ -137,9 +454,33 @@ func (s *Session) SaveToFile(location string) (string, error) {
 		return "", err
 	}
 
+	return writeReportFile(location, prettyJSON.Bytes())
+}
+
+// SaveReportToFile exports scan results to file in the format given by options
+func (s *Session) SaveReportToFile(location string) (string, error) {
+	switch *s.Options.Format {
+	case FormatSARIF:
+		return s.SaveSARIFToFile(location)
+	case FormatHTML:
+		return s.SaveHTMLToFile(location)
+	case FormatJSON, "":
+		return s.SaveToFile(location)
+	default:
+		return "", fmt.Errorf("unsupported report format: %s", *s.Options.Format)
+	}
+}
+
+func writeReportFile(location string, data []byte) (string, error) {
+	// get absolute path
+	absPath, err := filepath.Abs(location)
+	if err != nil {
+		return "", err
+	}
+
 	// if exists write to file
 	if filehandler.FileExists(absPath) {
-		err = ioutil.WriteFile(absPath, prettyJSON.Bytes(), 0644)
+		err = ioutil.WriteFile(absPath, data, 0644)
 		if err != nil {
 			return "", err
 		}
@@ -160,7 +501,7 @@ func (s *Session) SaveToFile(location string) (string, error) {
 	}
 
 	// write to file
-	err = ioutil.WriteFile(absPath, prettyJSON.Bytes(), 0644)
+	err = ioutil.WriteFile(absPath, data, 0644)
 	if err != nil {
 		return "", err
 	}
@@ -179,30 +520,3 @@ func (s *Session) AddRepository(repository *gitprovider.Repository) {
 	}
 	s.Repositories = append(s.Repositories, repository)
 }
-
-// ValidateNewSession validates new session
-func ValidateNewSession(session *Session) error {
-	// var err error
-
-	// if session.Options, err = ParseOptions(); err != nil {
-	//   return err
-	// }
-
-	if *session.Options.Report != "" && filehandler.FileExists(*session.Options.Report) {
-		return fmt.Errorf("file: %s already exists", *session.Options.Report)
-	}
-
-	if *session.Options.Load != "" {
-		if !filehandler.FileExists(*session.Options.Load) {
-			return fmt.Errorf("session file %s does not exist or is not readable", *session.Options.Load)
-		}
-		data, err := ioutil.ReadFile(*session.Options.Load)
-		if err != nil {
-			return err
-		}
-		if err := json.Unmarshal(data, &session); err != nil {
-			return fmt.Errorf("session file %s is corrupt or not generated this version", *session.Options.Load)
-		}
-	}
-	return nil
-}
@@ -6,10 +6,12 @@
 package session
 
 import (
+	"encoding/json"
 	"flag"
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -17,25 +19,43 @@ import (
 	"github.com/grab/secret-scanner/scanner/gitprovider"
 
 	"github.com/grab/secret-scanner/scanner/findings"
+	"github.com/grab/secret-scanner/scanner/ignore"
 
 	"github.com/grab/secret-scanner/scanner/options"
+	"github.com/grab/secret-scanner/scanner/state"
 )
 
 var defaultOptions = options.Options{
-	CommitDepth: flag.Int("commit-depth", 500, "Number of repository commits to process"),
-	Threads:     flag.Int("threads", 0, "Number of concurrent threads (default number of logical CPUs)"),
-	Report:      flag.String("save", "", "Save session to file"),
-	Load:        flag.String("load", "", "Load session file"),
-	Silent:      flag.Bool("silent", false, "Suppress all output except for errors"),
-	Debug:       flag.Bool("debug", false, "Print debugging information"),
-	GitProvider: flag.String("git", "", "Specify type of git provider (Eg. github, gitlab, bitbucket)"),
-	BaseURL:     flag.String("baseurl", "", "Specify Git provider base URL"),
-	Token:       flag.String("token", "", "Specify Git provider token"),
-	EnvFilePath: flag.String("env", "", ".env file path containing Git provider base URLs and tokens"),
-	ScanTarget:  flag.String("scan-target", "", "Sub-directory within the repository to scan"),
-	Repos:       flag.String("repo-list", "", "CSV file containing the list of whitelisted repositories to scan"),
-	LocalPath:   flag.String("git-scan-path", "", "Specify the local path to scan"),
-	UI:          flag.Bool("ui", true, "Serves up local UI for scan results if true, defaults to true"),
+	CommitDepth:   flag.Int("commit-depth", 500, "Number of repository commits to process"),
+	Threads:       flag.Int("threads", 0, "Number of concurrent threads (default number of logical CPUs)"),
+	Report:        flag.String("save", "", "Save session to file"),
+	Load:          flag.String("load", "", "Load session file"),
+	Silent:        flag.Bool("silent", false, "Suppress all output except for errors"),
+	Debug:         flag.Bool("debug", false, "Print debugging information"),
+	GitProvider:   flag.String("git", "", "Specify type of git provider (Eg. github, gitlab, bitbucket)"),
+	BaseURL:       flag.String("baseurl", "", "Specify Git provider base URL"),
+	Token:         flag.String("token", "", "Specify Git provider token"),
+	EnvFilePath:   flag.String("env", "", ".env file path containing Git provider base URLs and tokens"),
+	ScanTarget:    flag.String("scan-target", "", "Sub-directory within the repository to scan"),
+	Repos:         flag.String("repo-list", "", "CSV file containing the list of whitelisted repositories to scan"),
+	LocalPath:     flag.String("git-scan-path", "", "Specify the local path to scan"),
+	UI:            flag.Bool("ui", true, "Serves up local UI for scan results if true, defaults to true"),
+	Entropy:       flag.Bool("entropy", false, "If true, strings with high Shannon entropy will be reported"),
+	IgnoreFile:    flag.String("ignore-file", "", "Global ignore file suppressing known false positives"),
+	Rules:         flag.String("rules", "", "Comma-separated list of YAML/JSON rule files containing custom signatures"),
+	RulesReplace:  flag.Bool("rules-replace", false, "If true, custom rules replace the built-in signatures"),
+	Format:        flag.String("format", "json", "Format of the output file (json, sarif)"),
+	DisableRules:  flag.String("disable-rules", "", "Comma-separated list of rule IDs which are not matched"),
+	EnableRules:   flag.String("enable-rules", "", "Comma-separated list of rule IDs to match"),
+	FailOn:        flag.String("fail-on", "", "Exit with status 1 if a finding has at least this severity"),
+	MinSeverity:   flag.String("min-severity", "info", "Only report findings with at least this severity"),
+	SecretSalt:    flag.String("secret-salt", "", "Salt of the secret hashes"),
+	StateStore:    flag.String("state-store", "", "State store URI"),
+	Tags:          flag.String("tags", "", "Comma-separated list of tags"),
+	BlobCache:     flag.Bool("blob-cache", false, "If true, hashes of content without matches are kept in the state store"),
+	JSONL:         flag.String("jsonl", "", "Append each finding as a JSON line to file as soon as it is found"),
+	TrackFindings: flag.Bool("track-findings", false, "If true, findings are tracked across scans in the state store"),
+	Verify:        flag.Bool("verify", false, "If true, found secrets are verified against the API of their issuer"),
 }
 
 func TestSession_Initialize(t *testing.T) {
@@ -88,6 +108,37 @@ func TestSession_InitStats(t *testing.T) {
 	sess.End()
 }
 
+func TestSession_InitBlobCache(t *testing.T) {
+	sess := createNewSession()
+	sess.Initialize(defaultOptions)
+	if sess.BlobCache != nil {
+		t.Errorf("Want no blob cache without -blob-cache, got %v", sess.BlobCache)
+	}
+	sess.End()
+
+	tempDir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Errorf("Cannot create temp. dir.: %v", err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	storePath := path.Join(tempDir, "state.json")
+	blobCache := true
+	opt := defaultOptions
+	opt.StateStore = &storePath
+	opt.BlobCache = &blobCache
+
+	sess = createNewSession()
+	sess.Initialize(opt)
+	defer sess.End()
+	if sess.BlobCache == nil || sess.BlobCache.Version() == "" {
+		t.Errorf("Want blob cache of the signature set, got %v", sess.BlobCache)
+	}
+}
+
 func TestSession_AddFinding(t *testing.T) {
 	sess := createNewSession()
 	sess.Initialize(defaultOptions)
@@ -98,6 +149,126 @@ func TestSession_AddFinding(t *testing.T) {
 	sess.End()
 }
 
+func TestSession_FailOnFindings(t *testing.T) {
+	sess := createNewSession()
+	sess.Initialize(defaultOptions)
+	defer sess.End()
+	sess.AddFinding(&findings.Finding{Severity: "medium"})
+
+	failOn := *sess.Options.FailOn
+	defer func() {
+		*sess.Options.FailOn = failOn
+	}()
+	cases := map[string]bool{"": false, "critical": false, "high": false, "medium": true, "low": true}
+	for severity, want := range cases {
+		*sess.Options.FailOn = severity
+		if got := sess.FailOnFindings(); got != want {
+			t.Errorf("Want %v for %q, got %v", want, severity, got)
+		}
+	}
+
+	// findings seen in an earlier scan do not fail the scan again
+	sess.Findings[0].Status = state.FindingStatusExisting
+	*sess.Options.FailOn = "low"
+	if sess.FailOnFindings() {
+		t.Errorf("Want false for existing finding, got true")
+	}
+}
+
+func TestSession_AddIgnoredFinding(t *testing.T) {
+	sess := createNewSession()
+	sess.Initialize(defaultOptions)
+	sess.AddIgnoredFinding(&findings.Finding{IgnoreReason: "test key"})
+	if len(sess.IgnoredFindings) != 1 {
+		t.Errorf("Want 1, got %v", len(sess.IgnoredFindings))
+	}
+	if len(sess.Findings) != 0 {
+		t.Errorf("Want 0, got %v", len(sess.Findings))
+	}
+	sess.End()
+}
+
+func TestSession_MarkFalsePositive(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Errorf("Cannot create temp. dir.: %v", err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	filepath := path.Join(tempDir, ignore.DefaultFilename)
+	opt := defaultOptions
+	opt.IgnoreFile = &filepath
+
+	sess := createNewSession()
+	sess.Initialize(opt)
+	defer sess.End()
+	sess.AddFinding(&findings.Finding{ID: "abc123", Description: "AWS Access Key ID", FilePath: "config.yml"})
+	sess.AddFinding(&findings.Finding{ID: "def456"})
+
+	marked, err := sess.MarkFalsePositive("abc123")
+	if err != nil || len(marked) != 1 {
+		t.Errorf("Want 1 marked finding, got %v, err: %v", len(marked), err)
+		return
+	}
+	if len(sess.Findings) != 1 || len(sess.IgnoredFindings) != 1 {
+		t.Errorf("Want 1 finding and 1 ignored finding, got %v and %v", len(sess.Findings), len(sess.IgnoredFindings))
+	}
+	if _, ignored := sess.IgnoreList.Match(marked[0], ""); !ignored {
+		t.Errorf("Want finding ignored by session ignore list")
+	}
+
+	list, err := ignore.Load(filepath)
+	if err != nil || !list.Fingerprints["abc123"] {
+		t.Errorf("Want fingerprint abc123 in ignore file, got %v, err: %v", list, err)
+	}
+
+	_, err = sess.MarkFalsePositive("abc123")
+	if err != ErrFindingNotFound {
+		t.Errorf("Want %v, got %v", ErrFindingNotFound, err)
+	}
+}
+
+func TestSession_AddFindingJSONL(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Errorf("Cannot create temp. dir.: %v", err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	filepath := path.Join(tempDir, "findings.jsonl")
+	opt := defaultOptions
+	opt.JSONL = &filepath
+
+	sess := createNewSession()
+	sess.Initialize(opt)
+	sess.AddFinding(&findings.Finding{ID: "abc123"})
+	sess.AddFinding(&findings.Finding{ID: "def456"})
+
+	// findings are written before the session ends
+	data, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Errorf("Want 2, got %v", len(lines))
+		return
+	}
+	finding := &findings.Finding{}
+	err = json.Unmarshal([]byte(lines[1]), finding)
+	if err != nil || finding.ID != "def456" {
+		t.Errorf("Want def456, got %v, err: %v", finding.ID, err)
+	}
+	sess.End()
+}
+
 func TestSession_AddRepository(t *testing.T) {
 	sess := createNewSession()
 	sess.Initialize(defaultOptions)
@@ -139,15 +310,70 @@ func TestSession_SaveToFile(t *testing.T) {
 	sess.End()
 }
 
+func TestSession_LoadFromFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Errorf("Cannot create temp. dir.: %v", err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	sess := createNewSession()
+	sess.Initialize(defaultOptions)
+	sess.AddFinding(&findings.Finding{ID: "abc123", Severity: "high", ContextLines: []string{"key = 'abc'"}})
+	sess.AddIgnoredFinding(&findings.Finding{ID: "def456"})
+	sess.AddRepository(&gitprovider.Repository{Name: "web"})
+	sess.Stats.Files = 42
+	sess.End()
+
+	filepath := path.Join(tempDir, "report.json")
+	_, err = sess.SaveToFile(filepath)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+
+	opt := defaultOptions
+	opt.Load = &filepath
+	loaded := createNewSession()
+	loaded.Initialize(opt)
+	defer loaded.End()
+	if len(loaded.Findings) != 1 || loaded.Findings[0].ContextLines[0] != "key = 'abc'" {
+		t.Errorf("Want finding abc123, got %v", loaded.Findings)
+	}
+	if len(loaded.IgnoredFindings) != 1 || len(loaded.Repositories) != 1 {
+		t.Errorf("Want 1 ignored finding and 1 repository, got %v and %v", len(loaded.IgnoredFindings), len(loaded.Repositories))
+	}
+	if loaded.Stats.Files != 42 || loaded.Stats.Status != StatusFinished {
+		t.Errorf("Want 42 files and status %v, got %v and %v", StatusFinished, loaded.Stats.Files, loaded.Stats.Status)
+	}
+
+	// SARIF logs do not contain the session
+	sarifPath := path.Join(tempDir, "report.sarif")
+	_, err = sess.SaveSARIFToFile(sarifPath)
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	err = createNewSession().LoadFromFile(sarifPath)
+	if err == nil {
+		t.Errorf("Want err, got no err")
+	}
+}
+
 func createNewSession() *Session {
 	return &Session{
-		Mutex:        sync.Mutex{},
-		Options:      options.Options{},
-		Out:          nil,
-		Stats:        nil,
-		Findings:     nil,
-		Repositories: nil,
-		Signatures:   nil,
-		StateStore:   nil,
+		Mutex:           sync.Mutex{},
+		Options:         options.Options{},
+		Out:             nil,
+		Stats:           nil,
+		Findings:        nil,
+		IgnoredFindings: nil,
+		Repositories:    nil,
+		Signatures:      nil,
+		IgnoreList:      nil,
+		StateStore:      nil,
 	}
 }
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package signatures
+
+// keywordMatcher finds multiple keywords in a single pass using the Aho-Corasick algorithm
+type keywordMatcher struct {
+	keywordCount int
+	// transitions of every state, failure transitions are resolved at build time
+	delta [][256]int32
+	// indexes of keywords ending at every state
+	output [][]int
+}
+
+// newKeywordMatcher builds the automaton of the given keywords
+func newKeywordMatcher(keywords []string) *keywordMatcher {
+	m := &keywordMatcher{keywordCount: len(keywords)}
+	m.addState()
+
+	// build the keyword trie
+	for i, keyword := range keywords {
+		state := int32(0)
+		for j := 0; j < len(keyword); j++ {
+			next := m.delta[state][keyword[j]]
+			if next < 0 {
+				next = m.addState()
+				m.delta[state][keyword[j]] = next
+			}
+			state = next
+		}
+		m.output[state] = append(m.output[state], i)
+	}
+
+	// resolve failure transitions breadth-first, so failure states are complete before their dependents
+	fail := make([]int32, len(m.delta))
+	var queue []int32
+	for c := 0; c < 256; c++ {
+		next := m.delta[0][c]
+		if next < 0 {
+			m.delta[0][c] = 0
+			continue
+		}
+		fail[next] = 0
+		queue = append(queue, next)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		m.output[state] = append(m.output[state], m.output[fail[state]]...)
+
+		for c := 0; c < 256; c++ {
+			next := m.delta[state][c]
+			if next < 0 {
+				m.delta[state][c] = m.delta[fail[state]][c]
+				continue
+			}
+			fail[next] = m.delta[fail[state]][c]
+			queue = append(queue, next)
+		}
+	}
+
+	return m
+}
+
+func (m *keywordMatcher) addState() int32 {
+	var row [256]int32
+	for c := range row {
+		row[c] = -1
+	}
+	m.delta = append(m.delta, row)
+	m.output = append(m.output, nil)
+	return int32(len(m.delta) - 1)
+}
+
+// find returns which keywords occur in text, indexed like the keywords the matcher was built with
+func (m *keywordMatcher) find(text string) []bool {
+	found := make([]bool, m.keywordCount)
+	remaining := m.keywordCount
+
+	state := int32(0)
+	for i := 0; i < len(text) && remaining > 0; i++ {
+		state = m.delta[state][text[i]]
+		for _, k := range m.output[state] {
+			if !found[k] {
+				found[k] = true
+				remaining--
+			}
+		}
+	}
+
+	return found
+}
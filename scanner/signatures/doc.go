@@ -12,6 +12,45 @@ const (
 	// TypePattern ...
 	TypePattern = "pattern"
 
+	// TypeEntropy ...
+	TypeEntropy = "entropy"
+
+	// MatchExact defines rule files' exact match type
+	MatchExact = "exact"
+
+	// MatchRegex defines rule files' regex match type
+	MatchRegex = "regex"
+
+	// AllowMarker marks a line on which matches are ignored, followed by the reason
+	AllowMarker = "secret-scanner:allow"
+
+	// SecretGroup is the name of the capture group holding the secret of a pattern
+	SecretGroup = "secret"
+
+	// SeverityCritical ...
+	SeverityCritical = "critical"
+
+	// SeverityHigh ...
+	SeverityHigh = "high"
+
+	// SeverityMedium ...
+	SeverityMedium = "medium"
+
+	// SeverityLow ...
+	SeverityLow = "low"
+
+	// SeverityInfo ...
+	SeverityInfo = "info"
+
+	// ConfidenceHigh ...
+	ConfidenceHigh = "high"
+
+	// ConfidenceMedium ...
+	ConfidenceMedium = "medium"
+
+	// ConfidenceLow ...
+	ConfidenceLow = "low"
+
 	// PartExtension ...
 	PartExtension = "extension"
 
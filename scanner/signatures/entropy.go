@@ -0,0 +1,195 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package signatures
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	// CharsetBase64 ...
+	CharsetBase64 = "base64"
+
+	// CharsetHex ...
+	CharsetHex = "hex"
+
+	// DefaultBase64EntropyThreshold is the default entropy threshold of base64 tokens
+	DefaultBase64EntropyThreshold = 4.5
+
+	// DefaultHexEntropyThreshold is the default entropy threshold of hex tokens
+	DefaultHexEntropyThreshold = 3.0
+
+	// DefaultEntropyMinLength is the default minimum length of tokens to be checked
+	DefaultEntropyMinLength = 20
+
+	// ShortTokenEntropyRatio is the share of the maximum entropy of their length which short tokens must reach.
+	// A token of n characters has at most log2(n) bits of entropy, e.g. 4.32 for 20 characters,
+	// so the threshold is lowered for tokens too short to reach it.
+	ShortTokenEntropyRatio = 0.95
+)
+
+const (
+	base64Chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=-_"
+	hexChars    = "0123456789abcdefABCDEF"
+)
+
+// EntropySignature flags base64 and hex tokens with high Shannon entropy
+type EntropySignature struct {
+	id              string
+	base64Threshold float64
+	hexThreshold    float64
+	minLength       int
+	description     string
+	comment         string
+	severity        string
+	confidence      string
+	tags            []string
+}
+
+// NewEntropySignature creates a high entropy string signature
+func NewEntropySignature(base64Threshold, hexThreshold float64, minLength int) EntropySignature {
+	return EntropySignature{
+		id:              "high-entropy-string",
+		base64Threshold: base64Threshold,
+		hexThreshold:    hexThreshold,
+		minLength:       minLength,
+		description:     "High entropy string",
+		comment:         "Randomly generated strings are likely to be keys or tokens",
+		severity:        SeverityMedium,
+		confidence:      ConfidenceLow,
+		tags:            []string{"generic"},
+	}
+}
+
+// Match checks if given file content contains high entropy tokens
+func (s EntropySignature) Match(file MatchFile) []*MatchResult {
+	var matchResults []*MatchResult
+
+	tokenStart := -1
+	content := file.ContentRaw
+	for i := 0; i <= len(content); i++ {
+		if i < len(content) && strings.IndexByte(base64Chars, content[i]) >= 0 {
+			if tokenStart < 0 {
+				tokenStart = i
+			}
+			continue
+		}
+
+		if tokenStart >= 0 {
+			token := content[tokenStart:i]
+			if s.isHighEntropy(token) {
+				matchResult := &MatchResult{
+					Filename:    file.Filename,
+					Path:        file.Path,
+					Extension:   file.Extension,
+					LineContent: token,
+				}
+				matchResult.Line, matchResult.Column = file.Position(tokenStart)
+				matchResult.FullLine = file.Line(matchResult.Line)
+				matchResult.SetInlineAllow(matchResult.FullLine)
+				matchResult.SetSecret(content, tokenStart, i)
+				matchResults = append(matchResults, matchResult)
+			}
+			tokenStart = -1
+		}
+	}
+
+	return matchResults
+}
+
+func (s EntropySignature) isHighEntropy(token string) bool {
+	if len(token) < s.minLength {
+		return false
+	}
+
+	threshold, charsetSize := s.base64Threshold, 64
+	if DetectCharset(token) == CharsetHex {
+		threshold, charsetSize = s.hexThreshold, 16
+	}
+
+	return ShannonEntropy(token) > math.Min(threshold, ShortTokenEntropyRatio*maxEntropy(len(token), charsetSize))
+}
+
+// maxEntropy returns the highest Shannon entropy of a token of the given length and charset size
+func maxEntropy(length, charsetSize int) float64 {
+	if length > charsetSize {
+		length = charsetSize
+	}
+	return math.Log2(float64(length))
+}
+
+// ID returns signature ID
+func (s EntropySignature) ID() string {
+	return s.id
+}
+
+// Description returns signature description
+func (s EntropySignature) Description() string {
+	return s.description
+}
+
+// Comment returns signature comment
+func (s EntropySignature) Comment() string {
+	return s.comment
+}
+
+// Part returns signature part type
+func (s EntropySignature) Part() string {
+	return PartContent
+}
+
+// Keywords returns nil, the signature is matched against every file
+func (s EntropySignature) Keywords() []string {
+	return nil
+}
+
+// Tags returns signature tags
+func (s EntropySignature) Tags() []string {
+	return s.tags
+}
+
+// Severity returns signature severity
+func (s EntropySignature) Severity() string {
+	return s.severity
+}
+
+// Confidence returns signature confidence
+func (s EntropySignature) Confidence() string {
+	return s.confidence
+}
+
+// DetectCharset returns the charset of a token, either hex or base64
+func DetectCharset(token string) string {
+	for i := 0; i < len(token); i++ {
+		if strings.IndexByte(hexChars, token[i]) < 0 {
+			return CharsetBase64
+		}
+	}
+	return CharsetHex
+}
+
+// ShannonEntropy calculates the Shannon entropy of a string in bits per character
+func ShannonEntropy(str string) float64 {
+	if str == "" {
+		return 0
+	}
+
+	frequencies := map[rune]float64{}
+	total := 0.0
+	for _, r := range str {
+		frequencies[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, count := range frequencies {
+		p := count / total
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package signatures
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShannonEntropy(t *testing.T) {
+	cases := map[string]float64{
+		"":         0,
+		"aaaa":     0,
+		"abab":     1,
+		"abcd":     2,
+		"01234567": 3,
+	}
+	for str, want := range cases {
+		if got := ShannonEntropy(str); math.Abs(got-want) > 1e-9 {
+			t.Errorf("Want %v for %q, got %v", want, str, got)
+		}
+	}
+}
+
+func TestDetectCharset(t *testing.T) {
+	if charset := DetectCharset("9f86d081884c7d659a2feaa0c55ad015"); charset != CharsetHex {
+		t.Errorf("Want %v, got %v", CharsetHex, charset)
+	}
+	if charset := DetectCharset("wJalrXUtnFEMI/K7MDENG/bPxRfiCYzEXAMPLEKEY"); charset != CharsetBase64 {
+		t.Errorf("Want %v, got %v", CharsetBase64, charset)
+	}
+}
+
+func TestEntropySignature_Match(t *testing.T) {
+	sig := NewEntropySignature(DefaultBase64EntropyThreshold, DefaultHexEntropyThreshold, DefaultEntropyMinLength)
+	content := "package main\n\n" +
+		"// getUserAccountInformationFromTheDatabase\n" +
+		"const key = \"wJalrXUtnFEMI/K7MDENG/bPxRfiCYzEXAMPLEKEY\"\n" +
+		"const hash = \"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b\"\n" +
+		"const short = \"Zx9Qp2\"\n"
+
+	matches := sig.Match(NewMatchFile("main.go", content))
+	if len(matches) != 2 {
+		t.Errorf("Want 2, got %v", len(matches))
+		return
+	}
+	if matches[0].Line != 4 || matches[0].LineContent != "wJalrXUtnFEMI/K7MDENG/bPxRfiCYzEXAMPLEKEY" {
+		t.Errorf("Want base64 key on line 4, got %v on line %v", matches[0].LineContent, matches[0].Line)
+	}
+	if matches[0].Secret != matches[0].LineContent || content[matches[0].SecretStart:matches[0].SecretEnd] != matches[0].Secret {
+		t.Errorf("Want secret span of token, got %v", matches[0].Secret)
+	}
+	if matches[1].Line != 5 {
+		t.Errorf("Want 5, got %v", matches[1].Line)
+	}
+}
+
+func TestEntropySignature_MinLength(t *testing.T) {
+	sig := NewEntropySignature(DefaultBase64EntropyThreshold, DefaultHexEntropyThreshold, DefaultEntropyMinLength)
+	cases := map[string]bool{
+		// 20 distinct characters have 4.32 bits of entropy, below the base64 threshold
+		"aB3dE5gH7jK9mN1pQ2sT": true,
+		// 18 distinct characters of 20
+		"aB3dE5gH7jK9mN1pQ2aB": true,
+		// 16 distinct characters of 20
+		"aB3dE5gH7jK9mN1paB3d": false,
+		// shorter than the minimum length
+		"aB3dE5gH7jK9mN1pQ2s": false,
+		// hex tokens reach the hex threshold at the minimum length
+		"9f86d081884c7d659a2f": true,
+		"00000000001111111111": false,
+	}
+	for token, want := range cases {
+		if got := sig.isHighEntropy(token); got != want {
+			t.Errorf("Want %v for %q (%.2f bits), got %v", want, token, ShannonEntropy(token), got)
+		}
+	}
+}
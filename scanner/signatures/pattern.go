@@ -7,15 +7,22 @@ package signatures
 
 import (
 	"regexp"
-	"strings"
 )
 
 // PatternSignature ...
 type PatternSignature struct {
+	id          string
 	part        string
 	match       *regexp.Regexp
+	keywords    []string
 	description string
 	comment     string
+	severity    string
+	confidence  string
+	tags        []string
+
+	// case sensitive content signatures are matched against the original instead of the lowercased content
+	caseSensitive bool
 }
 
 // Match checks if given file matches with signature
@@ -30,31 +37,73 @@ func (s PatternSignature) Match(file MatchFile) []*MatchResult {
 		haystack = &file.Extension
 	case PartContent:
 		haystack = &file.Content
+		if s.caseSensitive {
+			haystack = &file.ContentRaw
+		}
 	default:
 		return nil
 	}
 
 	var matchResults []*MatchResult
-	contentBytes := []byte(file.ContentRaw)
-	locations := s.match.FindAllIndex([]byte(*haystack), -1)
+	locations := s.match.FindAllStringSubmatchIndex(*haystack, -1)
+	secretGroup := s.secretGroup()
+
+	// offsets in the lowercased content are converted to offsets in the original content
+	if haystack == &file.Content {
+		for _, loc := range locations {
+			for i := range loc {
+				loc[i] = file.RawOffset(loc[i])
+			}
+		}
+	}
 
 	for _, loc := range locations {
-		contentBytesBefLine := contentBytes[0 : loc[1]-1]
-		befLines := strings.Split(string(contentBytesBefLine), "\n")
-		lineNo := len(befLines)
+		matchResult := &MatchResult{
+			Filename:  file.Filename,
+			Path:      file.Path,
+			Extension: file.Extension,
+		}
+
+		// locations of file name and path matches are not within the content
+		if s.part != PartContent {
+			matchResult.LineContent = (*haystack)[loc[0]:loc[1]]
+			matchResults = append(matchResults, matchResult)
+			continue
+		}
+
+		matchResult.Line, matchResult.Column = file.Position(loc[0])
+		matchResult.LineContent = file.ContentRaw[loc[0]:loc[1]]
+		matchResult.FullLine = file.Line(matchResult.Line)
+		matchResult.SetInlineAllow(matchResult.FullLine)
 
-		matchResults = append(matchResults, &MatchResult{
-			Filename:    file.Filename,
-			Path:        file.Path,
-			Extension:   file.Extension,
-			Line:        uint64(lineNo),
-			LineContent: string(contentBytes[loc[0]:loc[1]]),
-		})
+		// the whole match is the secret if there is no secret group or it did not participate in the match
+		if secretGroup > 0 && loc[2*secretGroup] >= 0 {
+			matchResult.SetSecret(file.ContentRaw, loc[2*secretGroup], loc[2*secretGroup+1])
+		} else {
+			matchResult.SetSecret(file.ContentRaw, loc[0], loc[1])
+		}
+
+		matchResults = append(matchResults, matchResult)
 	}
 
 	return matchResults
 }
 
+// secretGroup returns the index of the secret capture group, or -1 if there is none
+func (s PatternSignature) secretGroup() int {
+	for i, name := range s.match.SubexpNames() {
+		if name == SecretGroup {
+			return i
+		}
+	}
+	return -1
+}
+
+// ID returns signature ID
+func (s PatternSignature) ID() string {
+	return s.id
+}
+
 // Description returns signature description
 func (s PatternSignature) Description() string {
 	return s.description
@@ -70,421 +119,697 @@ func (s PatternSignature) Part() string {
 	return s.part
 }
 
+// Severity returns signature severity
+func (s PatternSignature) Severity() string {
+	if s.severity == "" {
+		return defaultSeverity(s.part)
+	}
+	return s.severity
+}
+
+// Confidence returns signature confidence
+func (s PatternSignature) Confidence() string {
+	if s.confidence == "" {
+		return defaultConfidence(s.part)
+	}
+	return s.confidence
+}
+
+// Tags returns signature tags
+func (s PatternSignature) Tags() []string {
+	return s.tags
+}
+
+// Keywords returns literals of which at least one occurs in every content match
+func (s PatternSignature) Keywords() []string {
+	return s.keywords
+}
+
 // PatternSignatures contains simple signatures
 var PatternSignatures = []Signature{
 	PatternSignature{
+		id:          "ssh-private-key-rsa",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^.*_rsa$`),
 		description: "Private SSH key",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"ssh", "key"},
 	},
 	PatternSignature{
+		id:          "ssh-private-key-dsa",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^.*_dsa$`),
 		description: "Private SSH key",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"ssh", "key"},
 	},
 	PatternSignature{
+		id:          "ssh-private-key-ed25519",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^.*_ed25519$`),
 		description: "Private SSH key",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"ssh", "key"},
 	},
 	PatternSignature{
+		id:          "ssh-private-key-ecdsa",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^.*_ecdsa$`),
 		description: "Private SSH key",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"ssh", "key"},
 	},
 	PatternSignature{
+		id:          "ssh-config-file",
 		part:        PartPath,
 		match:       regexp.MustCompile(`\.?ssh/config$`),
 		description: "SSH configuration file",
 		comment:     "",
+		tags:        []string{"ssh", "config"},
 	},
 	PatternSignature{
+		id:          "key-file",
 		part:        PartExtension,
 		match:       regexp.MustCompile(`^key(pair)?$`),
 		description: "Potential cryptographic private key",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"key"},
 	},
 	PatternSignature{
+		id:          "shell-history-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?(bash_|zsh_|sh_|z)?history$`),
 		description: "Shell command history file",
 		comment:     "",
+		tags:        []string{"shell", "history"},
 	},
 	PatternSignature{
+		id:          "mysql-history-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?mysql_history$`),
 		description: "MySQL client command history file",
 		comment:     "",
+		tags:        []string{"database", "history"},
 	},
 	PatternSignature{
+		id:          "psql-history-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?psql_history$`),
 		description: "PostgreSQL client command history file",
 		comment:     "",
+		tags:        []string{"database", "history"},
 	},
 	PatternSignature{
+		id:          "pgpass-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?pgpass$`),
 		description: "PostgreSQL password file",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"database", "password"},
 	},
 	PatternSignature{
+		id:          "irb-history-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?irb_history$`),
 		description: "Ruby IRB console history file",
 		comment:     "",
+		tags:        []string{"ruby", "history"},
 	},
 	PatternSignature{
+		id:          "pidgin-accounts-file",
 		part:        PartPath,
 		match:       regexp.MustCompile(`\.?purple/accounts\.xml$`),
 		description: "Pidgin chat client account configuration file",
 		comment:     "",
+		tags:        []string{"chat", "config"},
 	},
 	PatternSignature{
+		id:          "xchat-server-list-file",
 		part:        PartPath,
 		match:       regexp.MustCompile(`\.?xchat2?/servlist_?\.conf$`),
 		description: "Hexchat/XChat IRC client server list configuration file",
 		comment:     "",
+		tags:        []string{"chat", "config"},
 	},
 	PatternSignature{
+		id:          "irssi-config-file",
 		part:        PartPath,
 		match:       regexp.MustCompile(`\.?irssi/config$`),
 		description: "Irssi IRC client configuration file",
 		comment:     "",
+		tags:        []string{"chat", "config"},
 	},
 	PatternSignature{
+		id:          "recon-ng-keys-database",
 		part:        PartPath,
 		match:       regexp.MustCompile(`\.?recon-ng/keys\.db$`),
 		description: "Recon-ng web reconnaissance framework API key database",
 		comment:     "",
+		tags:        []string{"database"},
 	},
 	PatternSignature{
+		id:          "dbeaver-data-sources-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?dbeaver-data-sources.xml$`),
 		description: "DBeaver SQL database manager configuration file",
 		comment:     "",
+		tags:        []string{"database", "config"},
 	},
 	PatternSignature{
+		id:          "mutt-config-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?muttrc$`),
 		description: "Mutt e-mail client configuration file",
 		comment:     "",
+		tags:        []string{"email", "config"},
 	},
 	PatternSignature{
+		id:          "s3cmd-config-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?s3cfg$`),
 		description: "S3cmd configuration file",
 		comment:     "",
+		tags:        []string{"cloud", "aws", "config"},
 	},
 	PatternSignature{
+		id:          "aws-cli-credentials-file",
 		part:        PartPath,
 		match:       regexp.MustCompile(`\.?aws/credentials$`),
 		description: "AWS CLI credentials file",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"cloud", "aws"},
 	},
 	PatternSignature{
+		id:          "sftp-config-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^sftp-config(\.json)?$`),
 		description: "SFTP connection configuration file",
 		comment:     "",
+		tags:        []string{"ftp", "config"},
 	},
 	PatternSignature{
+		id:          "t-twitter-config-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?trc$`),
 		description: "T command-line Twitter client configuration file",
 		comment:     "",
+		tags:        []string{"social", "config"},
 	},
 	PatternSignature{
+		id:          "gitrob-config-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?gitrobrc$`),
 		description: "Well, this is awkward... Gitrob configuration file",
 		comment:     "",
+		tags:        []string{"vcs", "config"},
 	},
 	PatternSignature{
+		id:          "shell-rc-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?(bash|zsh|csh)rc$`),
 		description: "Shell configuration file",
 		comment:     "Shell configuration files can contain passwords, API keys, hostnames and other goodies",
+		tags:        []string{"shell", "config"},
 	},
 	PatternSignature{
+		id:          "shell-profile-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?(bash_|zsh_)?profile$`),
 		description: "Shell profile configuration file",
 		comment:     "Shell configuration files can contain passwords, API keys, hostnames and other goodies",
+		tags:        []string{"shell", "config"},
 	},
 	PatternSignature{
+		id:          "shell-aliases-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?(bash_|zsh_)?aliases$`),
 		description: "Shell command alias configuration file",
 		comment:     "Shell configuration files can contain passwords, API keys, hostnames and other goodies",
+		tags:        []string{"shell", "config"},
 	},
 	PatternSignature{
+		id:          "php-config-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`config(\.inc)?\.php$`),
 		description: "PHP configuration file",
 		comment:     "",
+		tags:        []string{"php", "config"},
 	},
 	PatternSignature{
+		id:          "gnome-keyring-file",
 		part:        PartExtension,
 		match:       regexp.MustCompile(`^key(store|ring)$`),
 		description: "GNOME Keyring database file",
 		comment:     "",
+		tags:        []string{"password-manager"},
 	},
 	PatternSignature{
+		id:          "keepass-database-file",
 		part:        PartExtension,
 		match:       regexp.MustCompile(`^kdbx?$`),
 		description: "KeePass password manager database file",
 		comment:     "Feed it to Hashcat and see if you're lucky",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"password-manager"},
 	},
 	PatternSignature{
+		id:          "sql-dump-file",
 		part:        PartExtension,
 		match:       regexp.MustCompile(`^sql(dump)?$`),
 		description: "SQL dump file",
 		comment:     "",
+		tags:        []string{"database"},
 	},
 	PatternSignature{
+		id:          "htpasswd-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?htpasswd$`),
 		description: "Apache htpasswd file",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"password"},
 	},
 	PatternSignature{
+		id:          "netrc-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^(\.|_)?netrc$`),
 		description: "Configuration file for auto-login process",
 		comment:     "Can contain username and password",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"password", "config"},
 	},
 	PatternSignature{
+		id:          "rubygems-credentials-file",
 		part:        PartPath,
 		match:       regexp.MustCompile(`\.?gem/credentials$`),
 		description: "Rubygems credentials file",
 		comment:     "Can contain API key for a rubygems.org account",
+		tags:        []string{"ruby", "package-registry"},
 	},
 	PatternSignature{
+		id:          "tugboat-config-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?tugboat$`),
 		description: "Tugboat DigitalOcean management tool configuration",
 		comment:     "",
+		tags:        []string{"cloud", "digitalocean", "config"},
 	},
 	PatternSignature{
+		id:          "doctl-config-file",
 		part:        PartPath,
 		match:       regexp.MustCompile(`doctl/config.yaml$`),
 		description: "DigitalOcean doctl command-line client configuration file",
 		comment:     "Contains DigitalOcean API key and other information",
+		tags:        []string{"cloud", "digitalocean", "config"},
 	},
 	PatternSignature{
+		id:          "git-credentials-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?git-credentials$`),
 		description: "git-credential-store helper credentials file",
 		comment:     "",
+		tags:        []string{"vcs", "password"},
 	},
 	PatternSignature{
+		id:          "hub-config-file",
 		part:        PartPath,
 		match:       regexp.MustCompile(`config/hub$`),
 		description: "GitHub Hub command-line client configuration file",
 		comment:     "Can contain GitHub API access token",
+		tags:        []string{"vcs", "github", "config"},
 	},
 	PatternSignature{
+		id:          "gitconfig-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?gitconfig$`),
 		description: "Git configuration file",
 		comment:     "",
+		tags:        []string{"vcs", "config"},
 	},
 	PatternSignature{
+		id:          "chef-private-key",
 		part:        PartPath,
 		match:       regexp.MustCompile(`\.?chef/(.*)\.pem$`),
 		description: "Chef private key",
 		comment:     "Can be used to authenticate against Chef servers",
+		tags:        []string{"key", "config"},
 	},
 	PatternSignature{
+		id:          "linux-shadow-file",
 		part:        PartPath,
 		match:       regexp.MustCompile(`etc/shadow$`),
 		description: "Potential Linux shadow file",
 		comment:     "Contains hashed passwords for system users",
+		tags:        []string{"password", "system"},
 	},
 	PatternSignature{
+		id:          "linux-passwd-file",
 		part:        PartPath,
 		match:       regexp.MustCompile(`etc/passwd$`),
 		description: "Potential Linux passwd file",
 		comment:     "Contains system user information",
+		tags:        []string{"system"},
 	},
 	PatternSignature{
+		id:          "docker-config-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?dockercfg$`),
 		description: "Docker configuration file",
 		comment:     "Can contain credentials for public or private Docker registries",
+		tags:        []string{"container", "config"},
 	},
 	PatternSignature{
+		id:          "npmrc-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?npmrc$`),
 		description: "NPM configuration file",
 		comment:     "Can contain credentials for NPM registries",
+		tags:        []string{"package-registry", "config"},
 	},
 	PatternSignature{
+		id:          "env-file",
 		part:        PartFilename,
 		match:       regexp.MustCompile(`^\.?env$`),
 		description: "Environment configuration file",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceLow,
+		tags:        []string{"config"},
 	},
 	PatternSignature{
+		id:          "private-key",
 		part:        PartContent,
 		match:       regexp.MustCompile(`(?i)-{5}begin ([dr]sa|ec|openssh)? private key-{5}`),
+		keywords:    []string{"private key"},
 		description: "Private Key",
 		comment:     "",
+		severity:    SeverityCritical,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"key"},
 	},
 	PatternSignature{
+		id:          "aws-access-key-id-value",
 		part:        PartContent,
 		match:       regexp.MustCompile(`(A3T[A-Z0-9]|AKIA|AGPA|AROA|AIPA|ANPA|ANVA|ASIA)[A-Z0-9]{16}`),
+		keywords:    []string{"a3t", "akia", "agpa", "aroa", "aipa", "anpa", "anva", "asia"},
 		description: "AWS Access Key ID Value",
 		comment:     "",
+		severity:    SeverityCritical,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"cloud", "aws"},
+
+		caseSensitive: true,
 	},
 	PatternSignature{
+		id:          "aws-access-key-id",
 		part:        PartContent,
-		match:       regexp.MustCompile("((\\\"|'|`)?((?i)aws)?_?((?i)access)_?((?i)key)?_?((?i)id)?(\\\"|'|`)?\\\\s{0,50}(:|=>|=)\\\\s{0,50}(\\\"|'|`)?(A3T[A-Z0-9]|AKIA|AGPA|AIDA|AROA|AIPA|ANPA|ANVA|ASIA)[A-Z0-9]{16}(\\\"|'|`)?)"),
+		match:       regexp.MustCompile(`(["'\x60]?(?i:aws)?_?(?i:access)_?(?i:key)?_?(?i:id)?["'\x60]?\s{0,50}(?::|=>|=)\s{0,50}["'\x60]?(?P<secret>(?:A3T[A-Z0-9]|AKIA|AGPA|AIDA|AROA|AIPA|ANPA|ANVA|ASIA)[A-Z0-9]{16})["'\x60]?)`),
+		keywords:    []string{"access"},
 		description: "AWS Access Key ID",
 		comment:     "",
+		severity:    SeverityCritical,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"cloud", "aws"},
+
+		caseSensitive: true,
 	},
 	PatternSignature{
+		id:          "aws-account-id",
 		part:        PartContent,
-		match:       regexp.MustCompile("((\\\"|'|`)?((?i)aws)?_?((?i)account)_?((?i)id)?(\\\"|'|`)?\\\\s{0,50}(:|=>|=)\\\\s{0,50}(\\\"|'|`)?[0-9]{4}-?[0-9]{4}-?[0-9]{4}(\\\"|'|`)?)"),
+		match:       regexp.MustCompile(`(["'\x60]?(?i:aws)?_?(?i:account)_?(?i:id)?["'\x60]?\s{0,50}(?::|=>|=)\s{0,50}["'\x60]?(?P<secret>[0-9]{4}-?[0-9]{4}-?[0-9]{4})["'\x60]?)`),
+		keywords:    []string{"account"},
 		description: "AWS Account ID",
 		comment:     "",
+		severity:    SeverityLow,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"cloud", "aws"},
 	},
 	PatternSignature{
+		id:          "aws-secret-access-key",
 		part:        PartContent,
-		match:       regexp.MustCompile("((\\\"|'|`)?((?i)aws)?_?((?i)secret)_?((?i)access)?_?((?i)key)?_?((?i)id)?(\\\"|'|`)?\\\\s{0,50}(:|=>|=)\\\\s{0,50}(\\\"|'|`)?[A-Za-z0-9/+=]{40}(\\\"|'|`)?)"),
+		match:       regexp.MustCompile(`(["'\x60]?(?i:aws)?_?(?i:secret)_?(?i:access)?_?(?i:key)?_?(?i:id)?["'\x60]?\s{0,50}(?::|=>|=)\s{0,50}["'\x60]?(?P<secret>[A-Za-z0-9/+=]{40})["'\x60]?)`),
+		keywords:    []string{"secret"},
 		description: "AWS Secret Access Key",
 		comment:     "",
+		severity:    SeverityCritical,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"cloud", "aws"},
 	},
 	PatternSignature{
+		id:          "aws-session-token",
 		part:        PartContent,
-		match:       regexp.MustCompile("((\\\"|'|`)?((?i)aws)?_?((?i)session)?_?((?i)token)?(\\\"|'|`)?\\\\s{0,50}(:|=>|=)\\\\s{0,50}(\\\"|'|`)?[A-Za-z0-9/+=]{16,}(\\\"|'|`)?)"),
+		match:       regexp.MustCompile(`(["'\x60]?(?i:aws)?_?(?i:session)_?(?i:token)["'\x60]?\s{0,50}(?::|=>|=)\s{0,50}["'\x60]?(?P<secret>[A-Za-z0-9/+=]{16,})["'\x60]?)`),
+		keywords:    []string{"session"},
 		description: "AWS Session Token",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceLow,
+		tags:        []string{"cloud", "aws"},
 	},
 	PatternSignature{
+		id:          "artifactory-token",
 		part:        PartContent,
-		match:       regexp.MustCompile("(?i)artifactory.{0,50}(\\\"|'|`)?[a-zA-Z0-9=]{112}(\\\"|'|`)?"),
+		match:       regexp.MustCompile("(?i)artifactory.{0,50}(\\\"|'|`)?(?P<secret>[a-zA-Z0-9=]{112})(\\\"|'|`)?"),
+		keywords:    []string{"artifactory"},
 		description: "Artifactory",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"ci", "package-registry"},
 	},
 	PatternSignature{
+		id:          "codeclimate-token",
 		part:        PartContent,
-		match:       regexp.MustCompile("(?i)codeclima.{0,50}(\\\"|'|`)?[0-9a-f]{64}(\\\"|'|`)?"),
+		match:       regexp.MustCompile("(?i)codeclima.{0,50}(\\\"|'|`)?(?P<secret>[0-9a-f]{64})(\\\"|'|`)?"),
+		keywords:    []string{"codeclima"},
 		description: "CodeClimate",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"ci"},
 	},
 	PatternSignature{
+		id:          "facebook-access-token",
 		part:        PartContent,
 		match:       regexp.MustCompile(`EAACEdEose0cBA[0-9A-Za-z]+`),
+		keywords:    []string{"eaacedeose0cba"},
 		description: "Facebook Access Token",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"social"},
+
+		caseSensitive: true,
 	},
 	PatternSignature{
+		id:          "google-service-account",
 		part:        PartContent,
-		match:       regexp.MustCompile("((\\\"|'|`)?type(\\\"|'|`)?\\\\s{0,50}(:|=>|=)\\\\s{0,50}(\\\"|'|`)?service_account(\\\"|'|`)?,?)"),
+		match:       regexp.MustCompile(`(["'\x60]?type["'\x60]?\s{0,50}(?::|=>|=)\s{0,50}["'\x60]?service_account["'\x60]?,?)`),
+		keywords:    []string{"service_account"},
 		description: "Google (GCM) Service account",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"cloud", "google"},
 	},
 	PatternSignature{
+		id:          "stripe-api-key",
 		part:        PartContent,
-		match:       regexp.MustCompile(`(?:r|s)k_[live|test]_[0-9a-zA-Z]{24}`),
+		match:       regexp.MustCompile(`[rs]k_(?:live|test)_[0-9a-zA-Z]{24,99}`),
+		keywords:    []string{"k_live_", "k_test_"},
 		description: "Stripe API key",
 		comment:     "",
+		severity:    SeverityCritical,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"payment"},
+
+		caseSensitive: true,
 	},
 	PatternSignature{
+		id:          "google-oauth-client-id",
 		part:        PartContent,
 		match:       regexp.MustCompile(`[0-9]+-[0-9A-Za-z_]{32}\.apps\.googleusercontent\.com`),
+		keywords:    []string{".apps.googleusercontent.com"},
 		description: "Google OAuth Key",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"cloud", "google"},
 	},
 	PatternSignature{
+		id:          "google-cloud-api-key",
 		part:        PartContent,
-		match:       regexp.MustCompile(`AIza[0-9A-Za-z\\-_]{35}`),
+		match:       regexp.MustCompile(`AIza[0-9A-Za-z\-_]{35}`),
+		keywords:    []string{"aiza"},
 		description: "Google Cloud API Key",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"cloud", "google"},
+
+		caseSensitive: true,
 	},
 	PatternSignature{
+		id:          "google-oauth-access-token",
 		part:        PartContent,
-		match:       regexp.MustCompile(`ya29\\.[0-9A-Za-z\\-_]+`),
+		match:       regexp.MustCompile(`ya29\.[0-9A-Za-z\-_]+`),
+		keywords:    []string{"ya29"},
 		description: "Google OAuth Access Token",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"cloud", "google"},
 	},
 	PatternSignature{
+		id:          "picatic-api-key",
 		part:        PartContent,
-		match:       regexp.MustCompile(`sk_[live|test]_[0-9a-z]{32}`),
+		match:       regexp.MustCompile(`sk_(?:live|test)_[0-9a-z]{32}`),
+		keywords:    []string{"sk_"},
 		description: "Picatic API key",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"payment"},
 	},
 	PatternSignature{
+		id:          "square-access-token",
 		part:        PartContent,
 		match:       regexp.MustCompile(`sq0atp-[0-9A-Za-z\-_]{22}`),
+		keywords:    []string{"sq0atp-"},
 		description: "Square Access Token",
 		comment:     "",
+		severity:    SeverityCritical,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"payment"},
 	},
 	PatternSignature{
+		id:          "square-oauth-secret",
 		part:        PartContent,
 		match:       regexp.MustCompile(`sq0csp-[0-9A-Za-z\-_]{43}`),
+		keywords:    []string{"sq0csp-"},
 		description: "Square OAuth Secret",
 		comment:     "",
+		severity:    SeverityCritical,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"payment"},
 	},
 	PatternSignature{
+		id:          "paypal-braintree-access-token",
 		part:        PartContent,
 		match:       regexp.MustCompile(`access_token\$production\$[0-9a-z]{16}\$[0-9a-f]{32}`),
+		keywords:    []string{"access_token$production$"},
 		description: "PayPal/Braintree Access Token",
 		comment:     "",
+		severity:    SeverityCritical,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"payment"},
 	},
 	PatternSignature{
+		id:          "amazon-mws-auth-token",
 		part:        PartContent,
 		match:       regexp.MustCompile(`amzn\.mws\.[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`),
+		keywords:    []string{"amzn.mws."},
 		description: "Amazon MWS Auth Token",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"aws", "commerce"},
 	},
 	PatternSignature{
+		id:          "twilio-api-key",
 		part:        PartContent,
 		match:       regexp.MustCompile(`SK[0-9a-fA-F]{32}`),
+		keywords:    []string{"sk"},
 		description: "Twilo API Key",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"communication"},
+
+		caseSensitive: true,
 	},
 	PatternSignature{
+		id:          "mailgun-api-key",
 		part:        PartContent,
 		match:       regexp.MustCompile(`key-[0-9a-zA-Z]{32}`),
+		keywords:    []string{"key-"},
 		description: "MailGun API Key",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"email"},
 	},
 	PatternSignature{
+		id:          "mailchimp-api-key",
 		part:        PartContent,
 		match:       regexp.MustCompile(`[0-9a-f]{32}-us[0-9]{12}`),
+		keywords:    []string{"-us"},
 		description: "MailChimp API Key",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"email"},
 	},
 	PatternSignature{
+		id:          "sshpass-password",
 		part:        PartContent,
-		match:       regexp.MustCompile(`sshpass -p.*['|\\\"]`),
+		match:       regexp.MustCompile(`sshpass -p.*['"]`),
+		keywords:    []string{"sshpass -p"},
 		description: "SSH Password",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"ssh", "password"},
 	},
 	PatternSignature{
+		id:          "outlook-webhook",
 		part:        PartContent,
-		match:       regexp.MustCompile(`(https\\://outlook\\.office.com/webhook/[0-9a-f-]{36}\\@)`),
+		match:       regexp.MustCompile(`(https://outlook\.office\.com/webhook/[0-9a-f-]{36}@)`),
+		keywords:    []string{"outlook"},
 		description: "Outlook Team",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"chat"},
 	},
 	PatternSignature{
+		id:          "sauce-token",
 		part:        PartContent,
-		match:       regexp.MustCompile("(?i)sauce.{0,50}(\\\"|'|`)?[0-9a-f-]{36}(\\\"|'|`)?"),
+		match:       regexp.MustCompile("(?i)sauce.{0,50}(\\\"|'|`)?(?P<secret>[0-9a-f-]{36})(\\\"|'|`)?"),
+		keywords:    []string{"sauce"},
 		description: "Sauce Token",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceLow,
+		tags:        []string{"ci"},
 	},
 	PatternSignature{
+		id:          "slack-token",
 		part:        PartContent,
 		match:       regexp.MustCompile(`(xox[pboa]-[0-9]{12}-[0-9]{12}-[0-9]{12}-[a-z0-9]{32})`),
+		keywords:    []string{"xox"},
 		description: "Slack Token",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"chat"},
 	},
 	//PatternSignature{
 	//	part:        PartContent,
@@ -493,33 +818,82 @@ var PatternSignatures = []Signature{
 	//	comment:     "",
 	//},
 	PatternSignature{
+		id:          "slack-webhook",
 		part:        PartContent,
 		match:       regexp.MustCompile(`https://hooks.slack.com/services/T[a-zA-Z0-9_]{8}/B[a-zA-Z0-9_]{8}/[a-zA-Z0-9_]{24}`),
+		keywords:    []string{"https://hooks"},
 		description: "Slack Webhook",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"chat"},
+
+		caseSensitive: true,
+	},
+	PatternSignature{
+		id:          "github-personal-access-token",
+		part:        PartContent,
+		match:       regexp.MustCompile(`ghp_[0-9a-zA-Z]{36}`),
+		keywords:    []string{"ghp_"},
+		description: "GitHub Personal Access Token",
+		comment:     "",
+		severity:    SeverityCritical,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"vcs", "github"},
+	},
+	PatternSignature{
+		id:          "github-fine-grained-token",
+		part:        PartContent,
+		match:       regexp.MustCompile(`github_pat_[0-9a-zA-Z_]{82}`),
+		keywords:    []string{"github_pat_"},
+		description: "GitHub Fine-grained Personal Access Token",
+		comment:     "",
+		severity:    SeverityCritical,
+		confidence:  ConfidenceHigh,
+		tags:        []string{"vcs", "github"},
 	},
 	PatternSignature{
+		id:          "sonarqube-token",
 		part:        PartContent,
-		match:       regexp.MustCompile("(?i)sonar.{0,50}(\\\"|'|`)?[0-9a-f]{40}(\\\"|'|`)?"),
+		match:       regexp.MustCompile("(?i)sonar.{0,50}(\\\"|'|`)?(?P<secret>[0-9a-f]{40})(\\\"|'|`)?"),
+		keywords:    []string{"sonar"},
 		description: "SonarQube Docs API Key",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceLow,
+		tags:        []string{"ci"},
 	},
 	PatternSignature{
+		id:          "hockeyapp-token",
 		part:        PartContent,
-		match:       regexp.MustCompile("(?i)hockey.{0,50}(\\\"|'|`)?[0-9a-f]{32}(\\\"|'|`)?"),
+		match:       regexp.MustCompile("(?i)hockey.{0,50}(\\\"|'|`)?(?P<secret>[0-9a-f]{32})(\\\"|'|`)?"),
+		keywords:    []string{"hockey"},
 		description: "HockeyApp",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceLow,
+		tags:        []string{"ci"},
 	},
 	PatternSignature{
+		id:          "uri-credentials",
 		part:        PartContent,
-		match:       regexp.MustCompile(`([\w+]{1,24})(://)([^$<]{1})([^\s";]{1,}):([^$<]{1})([^\s";]{1,})@[-a-zA-Z0-9@:%._\+~#=]{1,256}\.[a-zA-Z0-9()]{1,24}([^\s]+)`),
+		match:       regexp.MustCompile(`([\w+]{1,24})(://)([^$<]{1})([^\s";]{1,}):(?P<secret>([^$<]{1})([^\s";]{1,}))@[-a-zA-Z0-9@:%._\+~#=]{1,256}\.[a-zA-Z0-9()]{1,24}([^\s]+)`),
+		keywords:    []string{"://"},
 		description: "Username and password in URI",
 		comment:     "",
+		severity:    SeverityHigh,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"password"},
 	},
 	PatternSignature{
+		id:          "oauth-token",
 		part:        PartContent,
-		match:       regexp.MustCompile(`(eyj[a-z0-9\\-_%]+.eyj[a-z0-9\\-_%]+.[a-z0-9\\-_%]+)|(refresh_token[\"']?\\s*[:=]\\s*[\"']?(?:[a-z0-9_]+-)+[a-z0-9_]+[\"']?)`),
+		match:       regexp.MustCompile(`(eyj[a-z0-9\-_%]+\.eyj[a-z0-9\-_%]+\.[a-z0-9\-_%]+)|(refresh_token["']?\s*[:=]\s*["']?(?P<secret>(?:[a-z0-9_]+-)+[a-z0-9_]+)["']?)`),
+		keywords:    []string{"eyj", "refresh_token"},
 		description: "Contains OAuth token",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceLow,
+		tags:        []string{"token"},
 	},
 }
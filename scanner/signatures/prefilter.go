@@ -0,0 +1,74 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package signatures
+
+import "strings"
+
+// Prefilter selects the signatures worth matching against a file.
+// Content signatures with keywords are only selected if one of their keywords occurs in the content,
+// all keywords are searched in a single pass over the content.
+type Prefilter struct {
+	signatures []Signature
+	// indexes of the signatures selected for every file
+	always []int
+	// indexes of the signatures selected by every keyword
+	keywordSignatures [][]int
+	matcher           *keywordMatcher
+}
+
+// NewPrefilter creates a prefilter for the given signatures
+func NewPrefilter(sigs []Signature) *Prefilter {
+	p := &Prefilter{signatures: sigs}
+
+	var keywords []string
+	keywordIndexes := map[string]int{}
+	for i, sig := range sigs {
+		if sig.Part() != PartContent || len(sig.Keywords()) == 0 {
+			p.always = append(p.always, i)
+			continue
+		}
+
+		for _, keyword := range sig.Keywords() {
+			// content is matched in lower case
+			keyword = strings.ToLower(keyword)
+			k, exists := keywordIndexes[keyword]
+			if !exists {
+				k = len(keywords)
+				keywordIndexes[keyword] = k
+				keywords = append(keywords, keyword)
+				p.keywordSignatures = append(p.keywordSignatures, nil)
+			}
+			p.keywordSignatures[k] = append(p.keywordSignatures[k], i)
+		}
+	}
+	p.matcher = newKeywordMatcher(keywords)
+
+	return p
+}
+
+// Select returns the signatures to match against a file, in their original order
+func (p *Prefilter) Select(file MatchFile) []Signature {
+	selected := make([]bool, len(p.signatures))
+	for _, i := range p.always {
+		selected[i] = true
+	}
+	for k, found := range p.matcher.find(file.Content) {
+		if !found {
+			continue
+		}
+		for _, i := range p.keywordSignatures[k] {
+			selected[i] = true
+		}
+	}
+
+	var sigs []Signature
+	for i, sig := range p.signatures {
+		if selected[i] {
+			sigs = append(sigs, sig)
+		}
+	}
+	return sigs
+}
@@ -0,0 +1,288 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package signatures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Rule defines a custom signature loaded from a rule file
+type Rule struct {
+	ID          string   `json:"id" yaml:"id"`
+	Part        string   `json:"part" yaml:"part"`
+	Type        string   `json:"type" yaml:"type"`
+	Match       string   `json:"match" yaml:"match"`
+	Description string   `json:"description" yaml:"description"`
+	Comment     string   `json:"comment" yaml:"comment"`
+	Severity    string   `json:"severity" yaml:"severity"`
+	Confidence  string   `json:"confidence" yaml:"confidence"`
+	Tags        []string `json:"tags" yaml:"tags"`
+
+	// regex content rules only
+	Keywords      []string `json:"keywords" yaml:"keywords"`
+	CaseSensitive bool     `json:"case_sensitive" yaml:"case_sensitive"`
+
+	// entropy rules only
+	Base64Threshold float64 `json:"base64_threshold" yaml:"base64_threshold"`
+	HexThreshold    float64 `json:"hex_threshold" yaml:"hex_threshold"`
+	MinLength       int     `json:"min_length" yaml:"min_length"`
+}
+
+// RuleFile contains the rules defined in a rule file
+type RuleFile struct {
+	Rules []*Rule `json:"rules" yaml:"rules"`
+}
+
+// Signature converts the rule into a signature
+func (r *Rule) Signature() (Signature, error) {
+	switch r.Part {
+	case PartExtension, PartFilename, PartPath, PartContent:
+	default:
+		return nil, fmt.Errorf("rule %s: invalid part %q", r.ID, r.Part)
+	}
+
+	if r.Severity != "" && !IsValidSeverity(r.Severity) {
+		return nil, fmt.Errorf("rule %s: invalid severity %q", r.ID, r.Severity)
+	}
+	if r.Confidence != "" && !IsValidConfidence(r.Confidence) {
+		return nil, fmt.Errorf("rule %s: invalid confidence %q", r.ID, r.Confidence)
+	}
+
+	if len(r.Keywords) > 0 && (r.Part != PartContent || (r.Type != MatchRegex && r.Type != TypePattern)) {
+		return nil, fmt.Errorf("rule %s: keywords are only supported by regex content rules", r.ID)
+	}
+	if r.CaseSensitive && (r.Part != PartContent || (r.Type != MatchRegex && r.Type != TypePattern)) {
+		return nil, fmt.Errorf("rule %s: case_sensitive is only supported by regex content rules", r.ID)
+	}
+
+	if r.Type == TypeEntropy {
+		return r.entropySignature()
+	}
+
+	if r.Match == "" {
+		return nil, fmt.Errorf("rule %s: match must not be empty", r.ID)
+	}
+
+	switch r.Type {
+	case MatchExact, TypeSimple:
+		return SimpleSignature{
+			id:          r.ID,
+			part:        r.Part,
+			match:       r.Match,
+			description: r.Description,
+			comment:     r.Comment,
+			severity:    r.Severity,
+			confidence:  r.Confidence,
+			tags:        r.Tags,
+		}, nil
+	case MatchRegex, TypePattern:
+		re, err := regexp.Compile(r.Match)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %v", r.ID, err)
+		}
+		return PatternSignature{
+			id:          r.ID,
+			part:        r.Part,
+			match:       re,
+			keywords:    r.Keywords,
+			description: r.Description,
+			comment:     r.Comment,
+			severity:    r.Severity,
+			confidence:  r.Confidence,
+			tags:        r.Tags,
+
+			caseSensitive: r.CaseSensitive,
+		}, nil
+	default:
+		return nil, fmt.Errorf("rule %s: invalid type %q", r.ID, r.Type)
+	}
+}
+
+func (r *Rule) entropySignature() (Signature, error) {
+	if r.Part != PartContent {
+		return nil, fmt.Errorf("rule %s: entropy rules only support the content part", r.ID)
+	}
+
+	sig := NewEntropySignature(DefaultBase64EntropyThreshold, DefaultHexEntropyThreshold, DefaultEntropyMinLength)
+	sig.id = r.ID
+	if r.Description != "" {
+		sig.description = r.Description
+	}
+	if r.Comment != "" {
+		sig.comment = r.Comment
+	}
+	if r.Severity != "" {
+		sig.severity = r.Severity
+	}
+	if len(r.Tags) > 0 {
+		sig.tags = r.Tags
+	}
+	if r.Confidence != "" {
+		sig.confidence = r.Confidence
+	}
+	if r.Base64Threshold > 0 {
+		sig.base64Threshold = r.Base64Threshold
+	}
+	if r.HexThreshold > 0 {
+		sig.hexThreshold = r.HexThreshold
+	}
+	if r.MinLength > 0 {
+		sig.minLength = r.MinLength
+	}
+
+	return sig, nil
+}
+
+// LoadRuleFile loads signatures from a YAML or JSON rule file
+func LoadRuleFile(path string) ([]Signature, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleFile := &RuleFile{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		// unknown fields are rejected as in YAML rule files, e.g. a misspelled severity
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(ruleFile)
+	case ".yml", ".yaml":
+		err = yaml.UnmarshalStrict(data, ruleFile)
+	default:
+		return nil, fmt.Errorf("rule file %s must have a .json, .yml or .yaml extension", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse rule file %s: %v", path, err)
+	}
+
+	var sigs []Signature
+	ids := map[string]bool{}
+	for _, rule := range ruleFile.Rules {
+		if rule.ID == "" {
+			return nil, fmt.Errorf("rule file %s: every rule must have an id", path)
+		}
+		if ids[rule.ID] {
+			return nil, fmt.Errorf("rule file %s: duplicate rule id %s", path, rule.ID)
+		}
+		ids[rule.ID] = true
+
+		sig, err := rule.Signature()
+		if err != nil {
+			return nil, fmt.Errorf("rule file %s: %v", path, err)
+		}
+		sigs = append(sigs, sig)
+	}
+
+	return sigs, nil
+}
+
+// LoadRuleFiles loads signatures from multiple rule files,
+// rules in later files replace rules with the same ID in earlier ones
+func LoadRuleFiles(paths []string) ([]Signature, error) {
+	var sigs []Signature
+	for _, p := range paths {
+		fileSigs, err := LoadRuleFile(p)
+		if err != nil {
+			return nil, err
+		}
+		sigs = MergeSignatures(sigs, fileSigs)
+	}
+	return sigs, nil
+}
+
+// MergeSignatures appends custom signatures to base signatures,
+// a custom signature replaces the base signature with the same ID
+func MergeSignatures(base, custom []Signature) []Signature {
+	merged := make([]Signature, len(base))
+	copy(merged, base)
+
+	index := map[string]int{}
+	for i, sig := range merged {
+		if sig.ID() != "" {
+			index[sig.ID()] = i
+		}
+	}
+
+	for _, sig := range custom {
+		if i, exists := index[sig.ID()]; exists {
+			merged[i] = sig
+			continue
+		}
+		if sig.ID() != "" {
+			index[sig.ID()] = len(merged)
+		}
+		merged = append(merged, sig)
+	}
+
+	return merged
+}
+
+// SelectSignatures selects signatures by ID and tag. If IDs or tags are enabled, only signatures
+// with an enabled ID or any enabled tag are selected, disabled IDs are never selected.
+func SelectSignatures(sigs []Signature, enableIDs, disableIDs, tags []string) ([]Signature, error) {
+	knownIDs := map[string]bool{}
+	knownTags := map[string]bool{}
+	for _, sig := range sigs {
+		knownIDs[sig.ID()] = true
+		for _, tag := range sig.Tags() {
+			knownTags[tag] = true
+		}
+	}
+	for _, ids := range [][]string{enableIDs, disableIDs} {
+		for _, id := range ids {
+			if !knownIDs[id] {
+				return nil, fmt.Errorf("unknown rule id %s", id)
+			}
+		}
+	}
+	for _, tag := range tags {
+		if !knownTags[tag] {
+			return nil, fmt.Errorf("no rule has tag %s", tag)
+		}
+	}
+
+	enabled := toSet(enableIDs)
+	disabled := toSet(disableIDs)
+	enabledTags := toSet(tags)
+	selectAll := len(enabled) == 0 && len(enabledTags) == 0
+
+	var selected []Signature
+	for _, sig := range sigs {
+		if disabled[sig.ID()] {
+			continue
+		}
+		if selectAll || enabled[sig.ID()] || hasAnyTag(sig, enabledTags) {
+			selected = append(selected, sig)
+		}
+	}
+	return selected, nil
+}
+
+func hasAnyTag(sig Signature, tags map[string]bool) bool {
+	for _, tag := range sig.Tags() {
+		if tags[tag] {
+			return true
+		}
+	}
+	return false
+}
+
+func toSet(values []string) map[string]bool {
+	set := map[string]bool{}
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package signatures
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+const yamlRules = `
+rules:
+  - id: internal-token
+    part: content
+    type: regex
+    match: itk_[a-z0-9]{8}
+    keywords: [itk_]
+    severity: critical
+    confidence: high
+    tags: [internal]
+    description: Internal token
+    comment: Rotate via the internal token service
+  - id: internal-config
+    part: filename
+    type: exact
+    match: internal.conf
+    description: Internal configuration file
+`
+
+const jsonRules = `{"rules":[{"id":"internal-token","part":"content","type":"regex","match":"ITK2_[A-Z0-9]{8}","case_sensitive":true,"description":"Internal token v2"}]}`
+
+func TestLoadRuleFile(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanup(tempDir)
+
+	sigs, err := LoadRuleFile(writeRuleFile(t, tempDir, "rules.yaml", yamlRules))
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if len(sigs) != 2 {
+		t.Errorf("Want 2, got %v", len(sigs))
+		return
+	}
+	if sigs[0].ID() != "internal-token" {
+		t.Errorf("Want internal-token, got %v", sigs[0].ID())
+	}
+	if len(sigs[0].Keywords()) != 1 || sigs[0].Keywords()[0] != "itk_" {
+		t.Errorf("Want [itk_], got %v", sigs[0].Keywords())
+	}
+	if len(sigs[0].Tags()) != 1 || sigs[0].Tags()[0] != "internal" {
+		t.Errorf("Want [internal], got %v", sigs[0].Tags())
+	}
+	if sigs[0].Severity() != SeverityCritical || sigs[0].Confidence() != ConfidenceHigh {
+		t.Errorf("Want critical high, got %v %v", sigs[0].Severity(), sigs[0].Confidence())
+	}
+	if sigs[0].Comment() != "Rotate via the internal token service" {
+		t.Errorf("Want comment, got %v", sigs[0].Comment())
+	}
+
+	matches := sigs[0].Match(NewMatchFile("main.go", "token := \"itk_abcd1234\""))
+	if len(matches) != 1 {
+		t.Errorf("Want 1, got %v", len(matches))
+	}
+	matches = sigs[1].Match(NewMatchFile("etc/internal.conf", ""))
+	if len(matches) != 1 {
+		t.Errorf("Want 1, got %v", len(matches))
+	}
+
+	sigs, err = LoadRuleFile(writeRuleFile(t, tempDir, "rules.json", jsonRules))
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+		return
+	}
+	if len(sigs) != 1 {
+		t.Errorf("Want 1, got %v", len(sigs))
+		return
+	}
+	matches = sigs[0].Match(NewMatchFile("main.go", "token := \"ITK2_ABCD1234\""))
+	if len(matches) != 1 || matches[0].Secret != "ITK2_ABCD1234" {
+		t.Errorf("Want ITK2_ABCD1234, got %v", matches)
+	}
+}
+
+func TestLoadRuleFile_Invalid(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanup(tempDir)
+
+	invalidRules := map[string]string{
+		"no-id.yaml":         "rules:\n  - part: content\n    type: regex\n    match: abc\n",
+		"bad-part.yaml":      "rules:\n  - id: a\n    part: body\n    type: regex\n    match: abc\n",
+		"bad-type.yaml":      "rules:\n  - id: a\n    part: content\n    type: glob\n    match: abc\n",
+		"bad-regex.yaml":     "rules:\n  - id: a\n    part: content\n    type: regex\n    match: \"[\"\n",
+		"duplicate.yaml":     "rules:\n  - id: a\n    part: content\n    type: regex\n    match: abc\n  - id: a\n    part: content\n    type: regex\n    match: def\n",
+		"bad-keywords.yaml":  "rules:\n  - id: a\n    part: filename\n    type: exact\n    match: abc\n    keywords: [abc]\n",
+		"bad-case.yaml":      "rules:\n  - id: a\n    part: path\n    type: regex\n    match: abc\n    case_sensitive: true\n",
+		"bad-severity.yaml":  "rules:\n  - id: a\n    part: content\n    type: regex\n    match: abc\n    severity: urgent\n",
+		"unknown-field.yaml": "rules:\n  - id: a\n    part: content\n    type: regex\n    match: abc\n    severty: high\n",
+		"unknown-field.json": `{"rules": [{"id": "a", "part": "content", "type": "regex", "match": "abc", "severty": "high"}]}`,
+		"bad-extension.txt":  "rules: []\n",
+	}
+	for name, content := range invalidRules {
+		_, err := LoadRuleFile(writeRuleFile(t, tempDir, name, content))
+		if err == nil {
+			t.Errorf("Want err for %s, got no err", name)
+		}
+	}
+}
+
+func TestMergeSignatures(t *testing.T) {
+	base := []Signature{
+		SimpleSignature{id: "a", part: PartExtension, match: ".a"},
+		SimpleSignature{id: "b", part: PartExtension, match: ".b"},
+	}
+	custom := []Signature{
+		SimpleSignature{id: "b", part: PartExtension, match: ".bb"},
+		SimpleSignature{id: "c", part: PartExtension, match: ".c"},
+	}
+
+	merged := MergeSignatures(base, custom)
+	if len(merged) != 3 {
+		t.Errorf("Want 3, got %v", len(merged))
+		return
+	}
+	if merged[1].(SimpleSignature).match != ".bb" {
+		t.Errorf("Want .bb, got %v", merged[1].(SimpleSignature).match)
+	}
+	if base[1].(SimpleSignature).match != ".b" {
+		t.Errorf("Base signatures should not be modified")
+	}
+}
+
+func TestSelectSignatures(t *testing.T) {
+	sigs := []Signature{
+		SimpleSignature{id: "a", part: PartExtension, match: ".a", tags: []string{"cloud"}},
+		SimpleSignature{id: "b", part: PartExtension, match: ".b", tags: []string{"cloud", "aws"}},
+		SimpleSignature{id: "c", part: PartExtension, match: ".c", tags: []string{"ssh"}},
+	}
+	cases := []struct {
+		enable, disable, tags []string
+		want                  string
+	}{
+		{nil, nil, nil, "abc"},
+		{[]string{"c"}, nil, nil, "c"},
+		{nil, []string{"b"}, nil, "ac"},
+		{nil, nil, []string{"cloud"}, "ab"},
+		{[]string{"c"}, []string{"b"}, []string{"cloud"}, "ac"},
+	}
+	for _, c := range cases {
+		selected, err := SelectSignatures(sigs, c.enable, c.disable, c.tags)
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+			continue
+		}
+		got := ""
+		for _, sig := range selected {
+			got += sig.ID()
+		}
+		if got != c.want {
+			t.Errorf("Want %v, got %v", c.want, got)
+		}
+	}
+
+	if _, err := SelectSignatures(sigs, []string{"unknown"}, nil, nil); err == nil {
+		t.Errorf("Want err for unknown ID, got no err")
+	}
+	if _, err := SelectSignatures(sigs, nil, nil, []string{"payment"}); err == nil {
+		t.Errorf("Want err for unknown tag, got no err")
+	}
+}
+
+func createTempDir(t *testing.T) string {
+	tempDir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Fatalf("Cannot create temp. dir.: %v", err)
+	}
+	return tempDir
+}
+
+func writeRuleFile(t *testing.T, dir, name, content string) string {
+	p := path.Join(dir, name)
+	err := ioutil.WriteFile(p, []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("Cannot write rule file: %v", err)
+	}
+	return p
+}
+
+func cleanup(path string) {
+	_ = os.RemoveAll(path)
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package signatures
+
+// Severities lists the severities from the least to the most severe
+var Severities = []string{SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// Confidences lists the confidence levels from the lowest to the highest
+var Confidences = []string{ConfidenceLow, ConfidenceMedium, ConfidenceHigh}
+
+// SeverityRank returns the position of a severity in Severities, or -1 for an unknown severity
+func SeverityRank(severity string) int {
+	return indexOf(Severities, severity)
+}
+
+// IsValidSeverity checks if severity is one of Severities
+func IsValidSeverity(severity string) bool {
+	return SeverityRank(severity) >= 0
+}
+
+// IsValidConfidence checks if confidence is one of Confidences
+func IsValidConfidence(confidence string) bool {
+	return indexOf(Confidences, confidence) >= 0
+}
+
+// defaultSeverity returns the severity of signatures without explicit severity,
+// file name and path matches only hint at files which may contain secrets
+func defaultSeverity(part string) string {
+	if part == PartContent {
+		return SeverityMedium
+	}
+	return SeverityLow
+}
+
+// defaultConfidence returns the confidence of signatures without explicit confidence
+func defaultConfidence(part string) string {
+	if part == PartContent {
+		return ConfidenceMedium
+	}
+	return ConfidenceLow
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
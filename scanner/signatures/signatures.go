@@ -6,17 +6,27 @@
 package signatures
 
 import (
+	"crypto/sha256"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Signature defines fields for a secret signature
 type Signature interface {
 	Match(file MatchFile) []*MatchResult
+	ID() string
 	Description() string
 	Comment() string
 	Part() string
+	Tags() []string
+	Keywords() []string
+	Severity() string
+	Confidence() string
 }
 
 // MatchFile contains details of a matching file
@@ -26,6 +36,12 @@ type MatchFile struct {
 	Extension  string
 	Content    string
 	ContentRaw string
+
+	// byte offsets of the line starts in ContentRaw
+	lineOffsets []int
+
+	// byte offsets in ContentRaw of the bytes of Content, nil if lowercasing kept every offset
+	rawOffsets []int
 }
 
 // MatchResult contains match info
@@ -34,7 +50,15 @@ type MatchResult struct {
 	Path        string
 	Extension   string
 	Line        uint64
+	Column      uint64
 	LineContent string
+	FullLine    string
+	// the secret value and its byte span in the content
+	Secret      string
+	SecretStart int
+	SecretEnd   int
+	Allowed     bool
+	AllowReason string
 }
 
 // IsSkippable determines if a given matched file can be ignored
@@ -98,13 +122,149 @@ func (f *MatchFile) IsTestContext() bool {
 func NewMatchFile(path string, content string) MatchFile {
 	_, filename := filepath.Split(path)
 	extension := filepath.Ext(path)
+	lowerContent, rawOffsets := toLowerWithOffsets(content)
 	return MatchFile{
-		Path:       path,
-		Filename:   filename,
-		Extension:  extension,
-		Content:    strings.ToLower(content),
-		ContentRaw: content,
+		Path:        path,
+		Filename:    filename,
+		Extension:   extension,
+		Content:     lowerContent,
+		ContentRaw:  content,
+		lineOffsets: newLineOffsets(content),
+		rawOffsets:  rawOffsets,
+	}
+}
+
+// toLowerWithOffsets lowercases content like strings.ToLower, but keeps invalid UTF-8 bytes.
+// The lowercase of some runes has a different length, e.g. U+023A or the Kelvin sign,
+// in which case the offsets in content of the bytes of the lowercased content are returned as well.
+func toLowerWithOffsets(content string) (string, []int) {
+	var lower strings.Builder
+	lower.Grow(len(content))
+	var rawOffsets []int
+	for i := 0; i < len(content); {
+		r, size := utf8.DecodeRuneInString(content[i:])
+		if r == utf8.RuneError && size == 1 {
+			lower.WriteByte(content[i])
+		} else {
+			lowerRune := unicode.ToLower(r)
+			if rawOffsets == nil && utf8.RuneLen(lowerRune) != size {
+				// offsets were kept up to here
+				rawOffsets = make([]int, lower.Len(), len(content)+1)
+				for j := range rawOffsets {
+					rawOffsets[j] = j
+				}
+			}
+			lower.WriteRune(lowerRune)
+		}
+		for rawOffsets != nil && len(rawOffsets) < lower.Len() {
+			rawOffsets = append(rawOffsets, i)
+		}
+		i += size
+	}
+	if rawOffsets != nil {
+		rawOffsets = append(rawOffsets, len(content))
+	}
+	return lower.String(), rawOffsets
+}
+
+// RawOffset converts a byte offset in Content to the byte offset in ContentRaw
+func (f MatchFile) RawOffset(offset int) int {
+	if f.rawOffsets == nil || offset < 0 || offset >= len(f.rawOffsets) {
+		return offset
 	}
+	return f.rawOffsets[offset]
+}
+
+// Position returns the line and column of a byte offset in the content, both starting from 1.
+// The column counts Unicode code points, as editors and SARIF viewers do, not bytes.
+func (f MatchFile) Position(offset int) (line, column uint64) {
+	offsets := f.offsets()
+	if offset < 0 {
+		offset = 0
+	} else if offset > len(f.ContentRaw) {
+		offset = len(f.ContentRaw)
+	}
+	i := sort.Search(len(offsets), func(i int) bool { return offsets[i] > offset }) - 1
+	return uint64(i + 1), uint64(utf8.RuneCountInString(f.ContentRaw[offsets[i]:offset]) + 1)
+}
+
+// Line returns the content of a line without line break, lines start from 1
+func (f MatchFile) Line(line uint64) string {
+	offsets := f.offsets()
+	if line < 1 || line > uint64(len(offsets)) {
+		return ""
+	}
+
+	start := offsets[line-1]
+	end := len(f.ContentRaw)
+	if line < uint64(len(offsets)) {
+		end = offsets[line] - 1
+	}
+	return strings.TrimSuffix(f.ContentRaw[start:end], "\r")
+}
+
+// Context returns the lines around a line, starting from the returned line number
+func (f MatchFile) Context(line uint64, radius uint64) (start uint64, lines []string) {
+	count := uint64(len(f.offsets()))
+	if line < 1 || line > count {
+		return 0, nil
+	}
+
+	start = 1
+	if line > radius {
+		start = line - radius
+	}
+	end := line + radius
+	if end > count {
+		end = count
+	}
+	for n := start; n <= end; n++ {
+		lines = append(lines, f.Line(n))
+	}
+	return start, lines
+}
+
+// offsets returns the line offsets, which are only computed here for files not created by NewMatchFile
+func (f MatchFile) offsets() []int {
+	if f.lineOffsets != nil {
+		return f.lineOffsets
+	}
+	return newLineOffsets(f.ContentRaw)
+}
+
+func newLineOffsets(content string) []int {
+	offsets := []int{0}
+	for i := 0; ; {
+		next := strings.IndexByte(content[i:], '\n')
+		if next < 0 {
+			return offsets
+		}
+		i += next + 1
+		offsets = append(offsets, i)
+	}
+}
+
+// SetSecret sets the secret to the given span of the content
+func (r *MatchResult) SetSecret(content string, start, end int) {
+	r.Secret = content[start:end]
+	r.SecretStart = start
+	r.SecretEnd = end
+}
+
+// SetInlineAllow marks the match as allowed if the line contains an inline allow comment
+func (r *MatchResult) SetInlineAllow(line string) {
+	idx := strings.Index(line, AllowMarker)
+	if idx < 0 {
+		return
+	}
+
+	reason := strings.TrimSpace(line[idx+len(AllowMarker):])
+	for _, commentEnd := range []string{"*/", "-->"} {
+		reason = strings.TrimSpace(strings.TrimSuffix(reason, commentEnd))
+	}
+
+	r.Allowed = true
+	r.AllowReason = reason
 }
 
 // LoadSignatures loads all signatures
@@ -112,3 +272,22 @@ func LoadSignatures() []Signature {
 	sig := SimpleSignatures
 	return append(sig, PatternSignatures...)
 }
+
+// Version returns a hash identifying a signature set,
+// it changes whenever a signature is added, removed or modified
+func Version(sigs []Signature) string {
+	h := sha256.New()
+	for _, sig := range sigs {
+		var definition string
+		switch s := sig.(type) {
+		case SimpleSignature:
+			definition = s.match
+		case PatternSignature:
+			definition = fmt.Sprintf("%s/%v", s.match.String(), s.caseSensitive)
+		case EntropySignature:
+			definition = fmt.Sprintf("%v/%v/%v/%v", s.base64Threshold, s.hexThreshold, s.minLength, ShortTokenEntropyRatio)
+		}
+		_, _ = fmt.Fprintf(h, "%T\x00%s\x00%s\x00%s\x00%s\n", sig, sig.ID(), sig.Part(), sig.Description(), definition)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
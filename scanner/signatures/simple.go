@@ -7,10 +7,14 @@ package signatures
 
 // SimpleSignature ...
 type SimpleSignature struct {
+	id          string
 	part        string
 	match       string
 	description string
 	comment     string
+	severity    string
+	confidence  string
+	tags        []string
 }
 
 // Match checks if given file matches with signature
@@ -44,6 +48,11 @@ func (s SimpleSignature) Match(file MatchFile) []*MatchResult {
 	return matchResults
 }
 
+// ID returns signature ID
+func (s SimpleSignature) ID() string {
+	return s.id
+}
+
 // Description returns signature description
 func (s SimpleSignature) Description() string {
 	return s.description
@@ -59,295 +68,435 @@ func (s SimpleSignature) Part() string {
 	return s.part
 }
 
+// Severity returns signature severity
+func (s SimpleSignature) Severity() string {
+	if s.severity == "" {
+		return defaultSeverity(s.part)
+	}
+	return s.severity
+}
+
+// Confidence returns signature confidence
+func (s SimpleSignature) Confidence() string {
+	if s.confidence == "" {
+		return defaultConfidence(s.part)
+	}
+	return s.confidence
+}
+
+// Tags returns signature tags
+func (s SimpleSignature) Tags() []string {
+	return s.tags
+}
+
+// Keywords returns nil, the signature is matched against every file
+func (s SimpleSignature) Keywords() []string {
+	return nil
+}
+
 // SimpleSignatures contains simple signatures
 var SimpleSignatures = []Signature{
 	// Extensions
 	SimpleSignature{
+		id:          "pem-file",
 		part:        PartExtension,
 		match:       ".pem",
 		description: "Potential cryptographic private key",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"key"},
 	},
 	SimpleSignature{
+		id:          "log-file",
 		part:        PartExtension,
 		match:       ".log",
 		description: "Log file",
 		comment:     "Log files can contain secret HTTP endpoints, session IDs, API keys and other goodies",
+		severity:    SeverityInfo,
+		confidence:  ConfidenceLow,
+		tags:        []string{"log"},
 	},
 	SimpleSignature{
+		id:          "pkcs12-file",
 		part:        PartExtension,
 		match:       ".pkcs12",
 		description: "Potential cryptographic key bundle",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceLow,
+		tags:        []string{"key"},
 	},
 	SimpleSignature{
+		id:          "p12-file",
 		part:        PartExtension,
 		match:       ".p12",
 		description: "Potential cryptographic key bundle",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceLow,
+		tags:        []string{"key"},
 	},
 	SimpleSignature{
+		id:          "pfx-file",
 		part:        PartExtension,
 		match:       ".pfx",
 		description: "Potential cryptographic key bundle",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceLow,
+		tags:        []string{"key"},
 	},
 	SimpleSignature{
+		id:          "asc-file",
 		part:        PartExtension,
 		match:       ".asc",
 		description: "Potential cryptographic key bundle",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceLow,
+		tags:        []string{"key"},
 	},
 	SimpleSignature{
+		id:          "pidgin-otr-private-key",
 		part:        PartFilename,
 		match:       "otr.private_key",
 		description: "Pidgin OTR private key",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"key", "chat"},
 	},
 	SimpleSignature{
+		id:          "openvpn-config-file",
 		part:        PartExtension,
 		match:       ".ovpn",
 		description: "OpenVPN client configuration file",
 		comment:     "",
+		tags:        []string{"vpn", "config"},
 	},
 	SimpleSignature{
+		id:          "azure-service-config-file",
 		part:        PartExtension,
 		match:       ".cscfg",
 		description: "Azure service configuration schema file",
 		comment:     "",
+		tags:        []string{"cloud", "azure", "config"},
 	},
 	SimpleSignature{
+		id:          "rdp-file",
 		part:        PartExtension,
 		match:       ".rdp",
 		description: "Remote Desktop connection file",
 		comment:     "",
+		tags:        []string{"remote-access"},
 	},
 	SimpleSignature{
+		id:          "mssql-database-file",
 		part:        PartExtension,
 		match:       ".mdf",
 		description: "Microsoft SQL database file",
 		comment:     "",
+		tags:        []string{"database"},
 	},
 	SimpleSignature{
+		id:          "mssql-compact-database-file",
 		part:        PartExtension,
 		match:       ".sdf",
 		description: "Microsoft SQL server compact database file",
 		comment:     "",
+		tags:        []string{"database"},
 	},
 	SimpleSignature{
+		id:          "sqlite-database-file",
 		part:        PartExtension,
 		match:       ".sqlite",
 		description: "SQLite database file",
 		comment:     "",
+		tags:        []string{"database"},
 	},
 	SimpleSignature{
+		id:          "sqlite3-database-file",
 		part:        PartExtension,
 		match:       ".sqlite3",
 		description: "SQLite3 database file",
 		comment:     "",
+		tags:        []string{"database"},
 	},
 	SimpleSignature{
+		id:          "bitlocker-recovery-key-file",
 		part:        PartExtension,
 		match:       ".bek",
 		description: "Microsoft BitLocker recovery key file",
 		comment:     "",
+		tags:        []string{"key", "encryption"},
 	},
 	SimpleSignature{
+		id:          "bitlocker-tpm-password-file",
 		part:        PartExtension,
 		match:       ".tpm",
 		description: "Microsoft BitLocker Trusted Platform Module password file",
 		comment:     "",
+		tags:        []string{"password", "encryption"},
 	},
 	SimpleSignature{
+		id:          "bitlocker-volume-file",
 		part:        PartExtension,
 		match:       ".fve",
 		description: "Windows BitLocker full volume encrypted data file",
 		comment:     "",
+		tags:        []string{"encryption"},
 	},
 	SimpleSignature{
+		id:          "java-keystore-file",
 		part:        PartExtension,
 		match:       ".jks",
 		description: "Java keystore file",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceLow,
+		tags:        []string{"key"},
 	},
 	SimpleSignature{
+		id:          "password-safe-database-file",
 		part:        PartExtension,
 		match:       ".psafe3",
 		description: "Password Safe database file",
 		comment:     "",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"password-manager"},
 	},
 	SimpleSignature{
+		id:          "1password-database-file",
 		part:        PartExtension,
 		match:       ".agilekeychain",
 		description: "1Password password manager database file",
 		comment:     "Feed it to Hashcat and see if you're lucky",
+		severity:    SeverityMedium,
+		confidence:  ConfidenceMedium,
+		tags:        []string{"password-manager"},
 	},
 	SimpleSignature{
+		id:          "apple-keychain-file",
 		part:        PartExtension,
 		match:       ".keychain",
 		description: "Apple Keychain database file",
 		comment:     "",
+		tags:        []string{"password-manager"},
 	},
 	SimpleSignature{
+		id:          "network-capture-file",
 		part:        PartExtension,
 		match:       ".pcap",
 		description: "Network traffic capture file",
 		comment:     "",
+		tags:        []string{"network"},
 	},
 	SimpleSignature{
+		id:          "gnucash-database-file",
 		part:        PartExtension,
 		match:       ".gnucash",
 		description: "GnuCash database file",
 		comment:     "",
+		tags:        []string{"database", "personal"},
 	},
 	SimpleSignature{
+		id:          "kde-wallet-file",
 		part:        PartExtension,
 		match:       ".kwallet",
 		description: "KDE Wallet Manager database file",
 		comment:     "",
+		tags:        []string{"password-manager"},
 	},
 	SimpleSignature{
+		id:          "tunnelblick-vpn-config-file",
 		part:        PartExtension,
 		match:       ".tblk",
 		description: "Tunnelblick VPN configuration file",
 		comment:     "",
+		tags:        []string{"vpn", "config"},
 	},
 	SimpleSignature{
+		id:          "day-one-journal-file",
 		part:        PartExtension,
 		match:       ".dayone",
 		description: "Day One journal file",
 		comment:     "Now it's getting creepy...",
+		tags:        []string{"personal"},
 	},
 
 	// Filenames
 	SimpleSignature{
+		id:          "rails-secret-token-file",
 		part:        PartFilename,
 		match:       "secret_token.rb",
 		description: "Ruby On Rails secret token configuration file",
 		comment:     "If the Rails secret token is known, it can allow for remote code execution (http://www.exploit-db.com/exploits/27527/)",
+		tags:        []string{"ruby", "config"},
 	},
 	SimpleSignature{
+		id:          "carrierwave-config-file",
 		part:        PartFilename,
 		match:       "carrierwave.rb",
 		description: "Carrierwave configuration file",
 		comment:     "Can contain credentials for cloud storage systems such as Amazon S3 and Google Storage",
+		tags:        []string{"ruby", "cloud", "config"},
 	},
 	SimpleSignature{
+		id:          "rails-database-config-file",
 		part:        PartFilename,
 		match:       "database.yml",
 		description: "Potential Ruby On Rails database configuration file",
 		comment:     "Can contain database credentials",
+		tags:        []string{"ruby", "database", "config"},
 	},
 	SimpleSignature{
+		id:          "omniauth-config-file",
 		part:        PartFilename,
 		match:       "omniauth.rb",
 		description: "OmniAuth configuration file",
 		comment:     "The OmniAuth configuration file can contain client application secrets",
+		tags:        []string{"ruby", "config"},
 	},
 	SimpleSignature{
+		id:          "django-settings-file",
 		part:        PartFilename,
 		match:       "settings.py",
 		description: "Django configuration file",
 		comment:     "Can contain database credentials, cloud storage system credentials, and other secrets",
+		tags:        []string{"python", "config"},
 	},
 
 	SimpleSignature{
+		id:          "jenkins-ssh-publisher-file",
 		part:        PartFilename,
 		match:       "jenkins.plugins.publish_over_ssh.BapSshPublisherPlugin.xml",
 		description: "Jenkins publish over SSH plugin file",
 		comment:     "",
+		tags:        []string{"ci", "ssh"},
 	},
 	SimpleSignature{
+		id:          "jenkins-credentials-file",
 		part:        PartFilename,
 		match:       "credentials.xml",
 		description: "Potential Jenkins credentials file",
 		comment:     "",
+		tags:        []string{"ci"},
 	},
 
 	SimpleSignature{
+		id:          "mediawiki-config-file",
 		part:        PartFilename,
 		match:       "LocalSettings.php",
 		description: "Potential MediaWiki configuration file",
 		comment:     "",
+		tags:        []string{"php", "config"},
 	},
 
 	SimpleSignature{
+		id:          "sequel-pro-bookmarks-file",
 		part:        PartFilename,
 		match:       "Favorites.plist",
 		description: "Sequel Pro MySQL database manager bookmark file",
 		comment:     "",
+		tags:        []string{"database"},
 	},
 	SimpleSignature{
+		id:          "little-snitch-config-file",
 		part:        PartFilename,
 		match:       "configuration.user.xpl",
 		description: "Little Snitch firewall configuration file",
 		comment:     "Contains traffic rules for applications",
+		tags:        []string{"network", "config"},
 	},
 
 	SimpleSignature{
+		id:          "jrnl-journal-file",
 		part:        PartFilename,
 		match:       "journal.txt",
 		description: "Potential jrnl journal file",
 		comment:     "Now it's getting creepy...",
+		tags:        []string{"personal"},
 	},
 	SimpleSignature{
+		id:          "chef-knife-config-file",
 		part:        PartFilename,
 		match:       "knife.rb",
 		description: "Chef Knife configuration file",
 		comment:     "Can contain references to Chef servers",
+		tags:        []string{"ruby", "config"},
 	},
 	SimpleSignature{
+		id:          "cpanel-proftpd-credentials-file",
 		part:        PartFilename,
 		match:       "proftpdpasswd",
 		description: "cPanel backup ProFTPd credentials file",
 		comment:     "Contains usernames and password hashes for FTP accounts",
+		tags:        []string{"ftp", "password"},
 	},
 	SimpleSignature{
+		id:          "robomongo-config-file",
 		part:        PartFilename,
 		match:       "robomongo.json",
 		description: "Robomongo MongoDB manager configuration file",
 		comment:     "Can contain credentials for MongoDB databases",
+		tags:        []string{"database", "config"},
 	},
 	SimpleSignature{
+		id:          "filezilla-config-file",
 		part:        PartFilename,
 		match:       "filezilla.xml",
 		description: "FileZilla FTP configuration file",
 		comment:     "Can contain credentials for FTP servers",
+		tags:        []string{"ftp", "config"},
 	},
 	SimpleSignature{
+		id:          "filezilla-recent-servers-file",
 		part:        PartFilename,
 		match:       "recentservers.xml",
 		description: "FileZilla FTP recent servers file",
 		comment:     "Can contain credentials for FTP servers",
+		tags:        []string{"ftp"},
 	},
 	SimpleSignature{
+		id:          "ventrilo-server-config-file",
 		part:        PartFilename,
 		match:       "ventrilo_srv.ini",
 		description: "Ventrilo server configuration file",
 		comment:     "Can contain passwords",
+		tags:        []string{"chat", "config"},
 	},
 	SimpleSignature{
+		id:          "terraform-variables-file",
 		part:        PartFilename,
 		match:       "terraform.tfvars",
 		description: "Terraform variable config file",
 		comment:     "Can contain credentials for terraform providers",
+		tags:        []string{"cloud", "config"},
 	},
 	SimpleSignature{
+		id:          "shell-exports-file",
 		part:        PartFilename,
 		match:       ".exports",
 		description: "Shell configuration file",
 		comment:     "Shell configuration files can contain passwords, API keys, hostnames and other goodies",
+		tags:        []string{"shell", "config"},
 	},
 	SimpleSignature{
+		id:          "shell-functions-file",
 		part:        PartFilename,
 		match:       ".functions",
 		description: "Shell configuration file",
 		comment:     "Shell configuration files can contain passwords, API keys, hostnames and other goodies",
+		tags:        []string{"shell", "config"},
 	},
 	SimpleSignature{
+		id:          "shell-extra-file",
 		part:        PartFilename,
 		match:       ".extra",
 		description: "Shell configuration file",
 		comment:     "Shell configuration files can contain passwords, API keys, hostnames and other goodies",
+		tags:        []string{"shell", "config"},
 	},
 }
@@ -11,4 +11,25 @@ const (
 
 	// DefaultStoreFile is the default scan histories file name
 	DefaultStoreFile = "scan-histories.json"
+
+	// DefaultSQLiteStoreFile is the default SQLite scan histories file name
+	DefaultSQLiteStoreFile = "scan-histories.db"
+
+	// SchemeJSON is the state store URI scheme of JSON files
+	SchemeJSON = "json"
+
+	// SchemeSQLite is the state store URI scheme of SQLite databases
+	SchemeSQLite = "sqlite"
+
+	// SettingSecretSalt is the name of the persisted salt of the secret hashes in the SQLite settings table
+	SettingSecretSalt = "secret_salt"
+
+	// FindingStatusNew marks a finding first seen in the latest scan, or seen again after it was resolved
+	FindingStatusNew = "new"
+
+	// FindingStatusExisting marks a finding also seen in an earlier scan
+	FindingStatusExisting = "existing"
+
+	// FindingStatusResolved marks a finding no longer present in the latest revision
+	FindingStatusResolved = "resolved"
 )
@@ -6,23 +6,43 @@
 package state
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
+	"sort"
+	"sync"
 
 	"github.com/mitchellh/go-homedir"
 )
 
 // JSONFileStore is a JSON-based storage for scan histories
 type JSONFileStore struct {
-	DataFile *os.File
-	Records  map[string]*History
+	DataFile       *os.File
+	Records        map[string]*History
+	FindingRecords map[string]*FindingRecord
+	BlobCache      *BlobCache
+	SecretSalt     string
+	filepath       string
+	mutex          sync.RWMutex
+}
+
+// jsonFileData is the content of a JSON store file.
+// Files written by earlier versions contain the histories array only.
+type jsonFileData struct {
+	Histories  []*History       `json:"histories"`
+	Findings   []*FindingRecord `json:"findings,omitempty"`
+	BlobCache  *BlobCache       `json:"blob_cache,omitempty"`
+	SecretSalt string           `json:"secret_salt,omitempty"`
 }
 
 // Initialize ...
 func (fs *JSONFileStore) Initialize(filepath string) error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
 	file, err := os.OpenFile(filepath, os.O_RDWR, 0644)
 	if err != nil {
 		return err
@@ -34,23 +54,37 @@ func (fs *JSONFileStore) Initialize(filepath string) error {
 	}
 
 	fs.DataFile = file
+	fs.filepath = filepath
 	fs.Records = map[string]*History{}
+	fs.FindingRecords = map[string]*FindingRecord{}
 
-	var records []*History
-	err = json.Unmarshal(recordBytes, &records)
+	data := &jsonFileData{}
+	if bytes.HasPrefix(bytes.TrimSpace(recordBytes), []byte("[")) {
+		err = json.Unmarshal(recordBytes, &data.Histories)
+	} else {
+		err = json.Unmarshal(recordBytes, data)
+	}
 	if err != nil {
 		return err
 	}
 
-	for _, record := range records {
+	for _, record := range data.Histories {
 		fs.Records[record.GetMapKey()] = record
 	}
+	for _, record := range data.Findings {
+		fs.FindingRecords[record.GetMapKey()] = record
+	}
+	fs.BlobCache = data.BlobCache
+	fs.SecretSalt = data.SecretSalt
 
 	return nil
 }
 
 // Close cleans up resources
 func (fs *JSONFileStore) Close() {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
 	if fs.DataFile != nil {
 		_ = fs.DataFile.Close()
 	}
@@ -85,6 +119,9 @@ func (fs *JSONFileStore) GetDefaultStorePath() (string, error) {
 
 // Get retrieves history from store
 func (fs *JSONFileStore) Get(gitprovider, repoID string) *History {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
 	val, exists := fs.Records[fmt.Sprintf("%s:%s", gitprovider, repoID)]
 	if exists {
 		return val
@@ -94,32 +131,180 @@ func (fs *JSONFileStore) Get(gitprovider, repoID string) *History {
 
 // Save persists records to file
 func (fs *JSONFileStore) Save(history *History) error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
 	fs.Records[history.GetMapKey()] = history
 
-	var histories []*History
+	return fs.write()
+}
+
+// List returns all histories ordered by git provider and repository
+func (fs *JSONFileStore) List() ([]*History, error) {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
+	histories := make([]*History, 0, len(fs.Records))
+	for _, history := range fs.Records {
+		histories = append(histories, history)
+	}
+	sort.Slice(histories, func(i, j int) bool {
+		return histories[i].GetMapKey() < histories[j].GetMapKey()
+	})
+	return histories, nil
+}
+
+// GetFinding retrieves the record of a finding from store
+func (fs *JSONFileStore) GetFinding(gitprovider, repoID, fingerprint string) *FindingRecord {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
+	record, exists := fs.FindingRecords[findingRecordKey(gitprovider, repoID, fingerprint)]
+	if !exists {
+		return nil
+	}
+	// callers modify the record before saving it
+	copied := *record
+	return &copied
+}
+
+// ListFindings returns the finding records of a repository ordered by fingerprint
+func (fs *JSONFileStore) ListFindings(gitprovider, repoID string) ([]*FindingRecord, error) {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
+	records := []*FindingRecord{}
+	for _, record := range fs.FindingRecords {
+		if record.GitProvider == gitprovider && record.RepoID == repoID {
+			copied := *record
+			records = append(records, &copied)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Fingerprint < records[j].Fingerprint
+	})
+	return records, nil
+}
+
+// SaveFindings persists finding records to file
+func (fs *JSONFileStore) SaveFindings(records []*FindingRecord) error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	for _, record := range records {
+		fs.FindingRecords[record.GetMapKey()] = record
+	}
+
+	return fs.write()
+}
+
+// LoadBlobCache returns the cached blob hashes of a signature set version
+func (fs *JSONFileStore) LoadBlobCache(version string) ([]string, error) {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
+	if fs.BlobCache == nil || fs.BlobCache.Version != version {
+		return nil, nil
+	}
+	return fs.BlobCache.Hashes, nil
+}
+
+// SaveBlobCache replaces the cached blob hashes, hashes of other signature set versions are dropped
+func (fs *JSONFileStore) SaveBlobCache(version string, hashes []string) error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	fs.BlobCache = &BlobCache{Version: version, Hashes: hashes}
+
+	return fs.write()
+}
+
+// LoadSecretSalt returns the persisted salt of the secret hashes, or an empty salt if none was saved
+func (fs *JSONFileStore) LoadSecretSalt() (string, error) {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
+	return fs.SecretSalt, nil
+}
+
+// SaveSecretSalt persists the salt of the secret hashes
+func (fs *JSONFileStore) SaveSecretSalt(salt string) error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	fs.SecretSalt = salt
+
+	return fs.write()
+}
 
+// write persists the store data to file, the caller must hold the lock
+func (fs *JSONFileStore) write() error {
+	data := &jsonFileData{
+		Histories:  []*History{},
+		BlobCache:  fs.BlobCache,
+		SecretSalt: fs.SecretSalt,
+	}
 	for _, val := range fs.Records {
-		histories = append(histories, val)
+		data.Histories = append(data.Histories, val)
+	}
+	for _, val := range fs.FindingRecords {
+		data.Findings = append(data.Findings, val)
 	}
 
-	jsonBytes, err := json.Marshal(histories)
+	jsonBytes, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 
-	err = fs.DataFile.Truncate(0)
+	// the data is written to a temporary file which replaces the store file,
+	// so that an interrupted write cannot leave a truncated store behind
+	stat, err := fs.DataFile.Stat()
 	if err != nil {
 		return err
 	}
-
-	_, err = fs.DataFile.WriteAt(jsonBytes, 0)
+	tempFile, err := ioutil.TempFile(path.Dir(fs.filepath), path.Base(fs.filepath)+".tmp-")
+	if err != nil {
+		return err
+	}
+	err = writeSyncFile(tempFile, jsonBytes, stat.Mode().Perm())
+	if err == nil {
+		err = os.Rename(tempFile.Name(), fs.filepath)
+	}
 	if err != nil {
+		_ = tempFile.Close()
+		_ = os.Remove(tempFile.Name())
 		return err
 	}
 
+	_ = fs.DataFile.Close()
+	fs.DataFile = tempFile
 	return nil
 }
 
+// writeSyncFile writes data to a file with the given permissions and flushes it to disk
+func writeSyncFile(file *os.File, data []byte, perm os.FileMode) error {
+	_, err := file.Write(data)
+	if err != nil {
+		return err
+	}
+	err = file.Chmod(perm)
+	if err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+func (fs *JSONFileStore) createStoreFileIfNotExist(filepath string) error {
+	_, err := os.Stat(filepath)
+	if err == nil {
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+	return fs.createDefaultStoreFile(path.Dir(filepath), path.Base(filepath))
+}
+
 func (fs *JSONFileStore) createDefaultStoreFile(dirPath, filename string) error {
 	err := os.MkdirAll(dirPath, 0700)
 	if err != nil {
@@ -0,0 +1,313 @@
+//go:build cgo
+// +build cgo
+
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package state
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/mitchellh/go-homedir"
+
+	// registers the sqlite3 database/sql driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS histories (
+	id           TEXT PRIMARY KEY,
+	git_provider TEXT NOT NULL,
+	repo_id      TEXT NOT NULL,
+	commit_hash  TEXT NOT NULL,
+	created_at   TEXT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS histories_repo ON histories (git_provider, repo_id);
+CREATE TABLE IF NOT EXISTS findings (
+	fingerprint  TEXT NOT NULL,
+	git_provider TEXT NOT NULL,
+	repo_id      TEXT NOT NULL,
+	file_path    TEXT NOT NULL,
+	signature_id TEXT NOT NULL,
+	description  TEXT NOT NULL,
+	status       TEXT NOT NULL,
+	first_seen   TEXT NOT NULL,
+	last_seen    TEXT NOT NULL,
+	resolved_at  TEXT NOT NULL,
+	PRIMARY KEY (git_provider, repo_id, fingerprint)
+);
+CREATE TABLE IF NOT EXISTS blob_cache (
+	version TEXT NOT NULL,
+	hash    TEXT NOT NULL,
+	PRIMARY KEY (version, hash)
+);
+CREATE TABLE IF NOT EXISTS settings (
+	name  TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+`
+
+// sqliteSupported reports whether the SQLite store is built in, the sqlite3 driver requires cgo
+const sqliteSupported = true
+
+// newSQLiteStore creates the SQLite store of a database file, or of the default file if the path is empty
+func newSQLiteStore(filepath string) (Store, string, error) {
+	sqliteStore := &SQLiteStore{}
+	if filepath == "" {
+		filepath, err := sqliteStore.GetDefaultStorePath()
+		return sqliteStore, filepath, err
+	}
+	return sqliteStore, filepath, os.MkdirAll(path.Dir(filepath), 0700)
+}
+
+// SQLiteStore is an embedded SQLite storage for scan histories
+type SQLiteStore struct {
+	DB *sql.DB
+}
+
+// Initialize opens the database and creates the schema
+func (ss *SQLiteStore) Initialize(filepath string) error {
+	// WAL lets readers proceed during writes, the busy timeout makes concurrent writers wait instead of failing
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate", filepath))
+	if err != nil {
+		return err
+	}
+	// SQLite allows a single writer, serialize access within the process
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(sqliteSchema)
+	if err != nil {
+		_ = db.Close()
+		return err
+	}
+
+	ss.DB = db
+	return nil
+}
+
+// Close cleans up resources
+func (ss *SQLiteStore) Close() {
+	if ss.DB != nil {
+		_ = ss.DB.Close()
+	}
+}
+
+// GetDefaultStorePath returns default database file path, creates dir if not found
+func (ss *SQLiteStore) GetDefaultStorePath() (string, error) {
+	userHomeDir, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	storeDirPath := path.Join(userHomeDir, DefaultStoreDir)
+
+	err = os.MkdirAll(storeDirPath, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(storeDirPath, DefaultSQLiteStoreFile), nil
+}
+
+// Get retrieves history from store
+func (ss *SQLiteStore) Get(gitprovider, repoID string) *History {
+	h := &History{}
+	row := ss.DB.QueryRow(
+		"SELECT id, git_provider, repo_id, commit_hash, created_at FROM histories WHERE git_provider = ? AND repo_id = ?",
+		gitprovider, repoID,
+	)
+	err := row.Scan(&h.ID, &h.GitProvider, &h.RepoID, &h.CommitHash, &h.CreatedAt)
+	if err != nil {
+		return nil
+	}
+	return h
+}
+
+// Save persists a history in a transaction
+func (ss *SQLiteStore) Save(history *History) error {
+	tx, err := ss.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"INSERT OR REPLACE INTO histories (id, git_provider, repo_id, commit_hash, created_at) VALUES (?, ?, ?, ?, ?)",
+		history.ID, history.GitProvider, history.RepoID, history.CommitHash, history.CreatedAt,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// List returns all histories ordered by git provider and repository
+func (ss *SQLiteStore) List() ([]*History, error) {
+	rows, err := ss.DB.Query("SELECT id, git_provider, repo_id, commit_hash, created_at FROM histories ORDER BY git_provider, repo_id")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	histories := []*History{}
+	for rows.Next() {
+		h := &History{}
+		err = rows.Scan(&h.ID, &h.GitProvider, &h.RepoID, &h.CommitHash, &h.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		histories = append(histories, h)
+	}
+	return histories, rows.Err()
+}
+
+const sqliteFindingColumns = "fingerprint, git_provider, repo_id, file_path, signature_id, description, status, first_seen, last_seen, resolved_at"
+
+// scanFindingRecord reads a finding record from a row of sqliteFindingColumns
+func scanFindingRecord(row interface{ Scan(...interface{}) error }) (*FindingRecord, error) {
+	r := &FindingRecord{}
+	err := row.Scan(&r.Fingerprint, &r.GitProvider, &r.RepoID, &r.FilePath, &r.SignatureID, &r.Description, &r.Status, &r.FirstSeen, &r.LastSeen, &r.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetFinding retrieves the record of a finding from store
+func (ss *SQLiteStore) GetFinding(gitprovider, repoID, fingerprint string) *FindingRecord {
+	row := ss.DB.QueryRow(
+		"SELECT "+sqliteFindingColumns+" FROM findings WHERE git_provider = ? AND repo_id = ? AND fingerprint = ?",
+		gitprovider, repoID, fingerprint,
+	)
+	record, err := scanFindingRecord(row)
+	if err != nil {
+		return nil
+	}
+	return record
+}
+
+// ListFindings returns the finding records of a repository ordered by fingerprint
+func (ss *SQLiteStore) ListFindings(gitprovider, repoID string) ([]*FindingRecord, error) {
+	rows, err := ss.DB.Query(
+		"SELECT "+sqliteFindingColumns+" FROM findings WHERE git_provider = ? AND repo_id = ? ORDER BY fingerprint",
+		gitprovider, repoID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	records := []*FindingRecord{}
+	for rows.Next() {
+		record, err := scanFindingRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// SaveFindings persists finding records in a transaction
+func (ss *SQLiteStore) SaveFindings(records []*FindingRecord) error {
+	tx, err := ss.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare("INSERT OR REPLACE INTO findings (" + sqliteFindingColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	for _, r := range records {
+		_, err = stmt.Exec(r.Fingerprint, r.GitProvider, r.RepoID, r.FilePath, r.SignatureID, r.Description, r.Status, r.FirstSeen, r.LastSeen, r.ResolvedAt)
+		if err != nil {
+			_ = stmt.Close()
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	_ = stmt.Close()
+
+	return tx.Commit()
+}
+
+// LoadBlobCache returns the cached blob hashes of a signature set version
+func (ss *SQLiteStore) LoadBlobCache(version string) ([]string, error) {
+	rows, err := ss.DB.Query("SELECT hash FROM blob_cache WHERE version = ?", version)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		err = rows.Scan(&hash)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
+
+// SaveBlobCache adds the cached blob hashes in a transaction, hashes of other signature set versions are dropped
+func (ss *SQLiteStore) SaveBlobCache(version string, hashes []string) error {
+	tx, err := ss.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM blob_cache WHERE version != ?", version)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	stmt, err := tx.Prepare("INSERT OR IGNORE INTO blob_cache (version, hash) VALUES (?, ?)")
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	for _, hash := range hashes {
+		_, err = stmt.Exec(version, hash)
+		if err != nil {
+			_ = stmt.Close()
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	_ = stmt.Close()
+
+	return tx.Commit()
+}
+
+// LoadSecretSalt returns the persisted salt of the secret hashes, or an empty salt if none was saved
+func (ss *SQLiteStore) LoadSecretSalt() (string, error) {
+	var salt string
+	err := ss.DB.QueryRow("SELECT value FROM settings WHERE name = ?", SettingSecretSalt).Scan(&salt)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return salt, err
+}
+
+// SaveSecretSalt persists the salt of the secret hashes
+func (ss *SQLiteStore) SaveSecretSalt(salt string) error {
+	_, err := ss.DB.Exec("INSERT OR REPLACE INTO settings (name, value) VALUES (?, ?)", SettingSecretSalt, salt)
+	return err
+}
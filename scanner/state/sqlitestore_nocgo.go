@@ -0,0 +1,19 @@
+//go:build !cgo
+// +build !cgo
+
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package state
+
+import "errors"
+
+// sqliteSupported reports whether the SQLite store is built in, the sqlite3 driver requires cgo
+const sqliteSupported = false
+
+// newSQLiteStore fails in builds without cgo, the SQLite store is not built in
+func newSQLiteStore(filepath string) (Store, string, error) {
+	return nil, "", errors.New("sqlite state store requires a cgo build; use json://")
+}
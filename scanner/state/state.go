@@ -20,11 +20,40 @@ type History struct {
 	CreatedAt   string `json:"created_at"`
 }
 
+// FindingRecord tracks a finding of a repository across scans by its fingerprint
+type FindingRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	GitProvider string `json:"git_provider"`
+	RepoID      string `json:"repo_id"`
+	FilePath    string `json:"file_path"`
+	SignatureID string `json:"signature_id"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	FirstSeen   string `json:"first_seen"`
+	LastSeen    string `json:"last_seen"`
+	ResolvedAt  string `json:"resolved_at,omitempty"`
+}
+
+// BlobCache contains the hashes of content without signature matches
+type BlobCache struct {
+	Version string   `json:"version"`
+	Hashes  []string `json:"hashes"`
+}
+
 // GetMapKey returns the scan history map key
 func (h *History) GetMapKey() string {
 	return fmt.Sprintf("%s:%s", h.GitProvider, h.RepoID)
 }
 
+// GetMapKey returns the finding record map key
+func (r *FindingRecord) GetMapKey() string {
+	return findingRecordKey(r.GitProvider, r.RepoID, r.Fingerprint)
+}
+
+func findingRecordKey(gitProvider, repoID, fingerprint string) string {
+	return fmt.Sprintf("%s:%s:%s", gitProvider, repoID, fingerprint)
+}
+
 // AssignID generates a hash and assign it to history ID field
 func (h *History) AssignID() {
 	hasher := sha256.New()
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// Store defines the methods of a scan histories storage
+type Store interface {
+	Initialize(filepath string) error
+	Get(gitprovider, repoID string) *History
+	Save(history *History) error
+	List() ([]*History, error)
+	GetFinding(gitprovider, repoID, fingerprint string) *FindingRecord
+	ListFindings(gitprovider, repoID string) ([]*FindingRecord, error)
+	SaveFindings(records []*FindingRecord) error
+	LoadBlobCache(version string) ([]string, error)
+	SaveBlobCache(version string, hashes []string) error
+	LoadSecretSalt() (string, error)
+	SaveSecretSalt(salt string) error
+	Close()
+}
+
+// NewStore creates and initializes a store from a URI,
+// e.g. json:///path/to/histories.json or sqlite:///path/to/histories.db.
+// An empty URI or path selects the default store location, URIs without scheme are treated as JSON file paths.
+func NewStore(uri string) (Store, error) {
+	scheme := SchemeJSON
+	filepath := uri
+	if i := strings.Index(uri, "://"); i >= 0 {
+		scheme = uri[:i]
+		filepath = uri[i+3:]
+	}
+
+	filepath, err := homedir.Expand(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	var store Store
+	switch scheme {
+	case SchemeJSON:
+		jsonStore := &JSONFileStore{}
+		if filepath == "" {
+			filepath, err = jsonStore.GetDefaultStorePath()
+		} else {
+			err = jsonStore.createStoreFileIfNotExist(filepath)
+		}
+		store = jsonStore
+	case SchemeSQLite:
+		store, filepath, err = newSQLiteStore(filepath)
+	default:
+		return nil, fmt.Errorf("unsupported state store scheme: %s", scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = store.Initialize(filepath)
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
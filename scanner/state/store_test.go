@@ -0,0 +1,351 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package state
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNewStore(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanup(tempDir)
+
+	cases := map[string]string{
+		"json://" + path.Join(tempDir, "histories.json"):   "*state.JSONFileStore",
+		path.Join(tempDir, "nested", "histories.json"):     "*state.JSONFileStore",
+		"sqlite://" + path.Join(tempDir, "histories.db"):   "*state.SQLiteStore",
+		"sqlite://" + path.Join(tempDir, "nested", "h.db"): "*state.SQLiteStore",
+	}
+	for uri, want := range cases {
+		store, err := NewStore(uri)
+		// the SQLite store is not built in without cgo
+		if !sqliteSupported && strings.HasPrefix(uri, "sqlite://") {
+			if err == nil {
+				t.Errorf("Want err without cgo, got %T", store)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", store); got != want {
+			t.Errorf("Want %v, got %v", want, got)
+		}
+		store.Close()
+	}
+
+	_, err := NewStore("redis://localhost")
+	if err == nil {
+		t.Errorf("Want err, got no err")
+	}
+}
+
+func TestStore_SaveConcurrently(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanup(tempDir)
+
+	for _, uri := range testStoreURIs(tempDir) {
+		store, err := NewStore(uri)
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+			continue
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				err := store.Save(Create("github", fmt.Sprintf("repo-%d", i), "abc123", "now"))
+				if err != nil {
+					t.Errorf("Want no err, got err: %v", err)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		err = store.Save(Create("github", "repo-0", "def456", "later"))
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+		}
+		store.Close()
+
+		// reopen to ensure everything was persisted
+		store, err = NewStore(uri)
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+			continue
+		}
+		for i := 0; i < 20; i++ {
+			if h := store.Get("github", fmt.Sprintf("repo-%d", i)); h == nil {
+				t.Errorf("Want history of repo-%d, got nil", i)
+			}
+		}
+		if h := store.Get("github", "repo-0"); h == nil || h.CommitHash != "def456" {
+			t.Errorf("Want def456, got %v", h)
+		}
+		if h := store.Get("gitlab", "repo-0"); h != nil {
+			t.Errorf("Want nil, got %v", h)
+		}
+		store.Close()
+	}
+}
+
+func TestStore_BlobCache(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanup(tempDir)
+
+	for _, uri := range testStoreURIs(tempDir) {
+		store, err := NewStore(uri)
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+			continue
+		}
+
+		err = store.SaveBlobCache("v1", []string{"a", "b"})
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+		}
+		hashes, err := store.LoadBlobCache("v1")
+		if err != nil || len(hashes) != 2 {
+			t.Errorf("Want 2 hashes, got %v (err: %v)", hashes, err)
+		}
+
+		// a new signature set version invalidates the cached hashes
+		err = store.SaveBlobCache("v2", []string{"c"})
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+		}
+		hashes, _ = store.LoadBlobCache("v1")
+		if len(hashes) != 0 {
+			t.Errorf("Want no hashes, got %v", hashes)
+		}
+		hashes, _ = store.LoadBlobCache("v2")
+		if len(hashes) != 1 || hashes[0] != "c" {
+			t.Errorf("Want [c], got %v", hashes)
+		}
+		store.Close()
+	}
+}
+
+func TestStore_SecretSalt(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanup(tempDir)
+
+	for _, uri := range testStoreURIs(tempDir) {
+		store, err := NewStore(uri)
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+			continue
+		}
+		salt, err := store.LoadSecretSalt()
+		if salt != "" || err != nil {
+			t.Errorf("Want no salt, got %q (err: %v)", salt, err)
+		}
+		err = store.SaveSecretSalt("pepper")
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+		}
+		store.Close()
+
+		// the salt is kept across scans
+		store, err = NewStore(uri)
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+			continue
+		}
+		salt, err = store.LoadSecretSalt()
+		if salt != "pepper" || err != nil {
+			t.Errorf("Want pepper, got %q (err: %v)", salt, err)
+		}
+		store.Close()
+	}
+}
+
+func TestStore_List(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanup(tempDir)
+
+	for _, uri := range testStoreURIs(tempDir) {
+		store, err := NewStore(uri)
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+			continue
+		}
+
+		for _, repoID := range []string{"repo-b", "repo-a"} {
+			_ = store.Save(Create("github", repoID, "abc123", "now"))
+		}
+		histories, err := store.List()
+		if err != nil || len(histories) != 2 {
+			t.Errorf("Want 2 histories, got %v (err: %v)", histories, err)
+			store.Close()
+			continue
+		}
+		if histories[0].RepoID != "repo-a" {
+			t.Errorf("Want repo-a, got %v", histories[0].RepoID)
+		}
+		store.Close()
+	}
+}
+
+func TestStore_Findings(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanup(tempDir)
+
+	for _, uri := range testStoreURIs(tempDir) {
+		store, err := NewStore(uri)
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+			continue
+		}
+
+		err = store.SaveFindings([]*FindingRecord{
+			{Fingerprint: "def456", GitProvider: "github", RepoID: "repo-a", Status: FindingStatusNew, FirstSeen: "t1", LastSeen: "t1"},
+			{Fingerprint: "abc123", GitProvider: "github", RepoID: "repo-a", Status: FindingStatusNew, FirstSeen: "t1", LastSeen: "t1"},
+			{Fingerprint: "abc123", GitProvider: "github", RepoID: "repo-b", Status: FindingStatusNew, FirstSeen: "t1", LastSeen: "t1"},
+		})
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+		}
+
+		record := store.GetFinding("github", "repo-a", "abc123")
+		if record == nil {
+			t.Errorf("Want record abc123, got nil")
+			store.Close()
+			continue
+		}
+		record.Status = FindingStatusResolved
+		record.ResolvedAt = "t2"
+		err = store.SaveFindings([]*FindingRecord{record})
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+		}
+		store.Close()
+
+		// reopen to ensure everything was persisted
+		store, err = NewStore(uri)
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+			continue
+		}
+		records, err := store.ListFindings("github", "repo-a")
+		if err != nil || len(records) != 2 {
+			t.Errorf("Want 2 records, got %v (err: %v)", records, err)
+			store.Close()
+			continue
+		}
+		if records[0].Fingerprint != "abc123" || records[0].Status != FindingStatusResolved || records[0].ResolvedAt != "t2" {
+			t.Errorf("Want resolved abc123, got %v", records[0])
+		}
+		if record := store.GetFinding("github", "repo-b", "abc123"); record == nil || record.Status != FindingStatusNew {
+			t.Errorf("Want new abc123 of repo-b, got %v", record)
+		}
+		if record := store.GetFinding("gitlab", "repo-a", "abc123"); record != nil {
+			t.Errorf("Want nil, got %v", record)
+		}
+		store.Close()
+	}
+}
+
+func TestJSONFileStore_LegacyFormat(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanup(tempDir)
+
+	storePath := path.Join(tempDir, "histories.json")
+	legacy := `[{"id":"1","git_provider":"github","repo_id":"repo-0","commit_hash":"abc123","created_at":"now"}]`
+	err := ioutil.WriteFile(storePath, []byte(legacy), 0644)
+	if err != nil {
+		t.Fatalf("Cannot write store file: %v", err)
+	}
+
+	store, err := NewStore(storePath)
+	if err != nil {
+		t.Fatalf("Want no err, got err: %v", err)
+	}
+	defer store.Close()
+
+	if h := store.Get("github", "repo-0"); h == nil || h.CommitHash != "abc123" {
+		t.Errorf("Want abc123, got %v", h)
+	}
+	err = store.SaveBlobCache("v1", []string{"a"})
+	if err != nil {
+		t.Errorf("Want no err, got err: %v", err)
+	}
+	if h := store.Get("github", "repo-0"); h == nil {
+		t.Errorf("Want history of repo-0, got nil")
+	}
+}
+
+func TestJSONFileStore_WriteReplacesFile(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanup(tempDir)
+
+	storePath := path.Join(tempDir, "histories.json")
+	err := ioutil.WriteFile(storePath, []byte("[]"), 0640)
+	if err != nil {
+		t.Fatalf("Cannot write store file: %v", err)
+	}
+
+	store, err := NewStore(storePath)
+	if err != nil {
+		t.Fatalf("Want no err, got err: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		err = store.Save(Create("github", fmt.Sprintf("repo-%d", i), "abc123", "now"))
+		if err != nil {
+			t.Errorf("Want no err, got err: %v", err)
+		}
+	}
+	store.Close()
+
+	// the store file is replaced by a complete temporary file, no temporary file is left behind
+	files, err := ioutil.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("Cannot read temp. dir.: %v", err)
+	}
+	if len(files) != 1 || files[0].Name() != "histories.json" || files[0].Mode().Perm() != 0640 {
+		t.Errorf("Want only histories.json with mode 0640, got %v files", len(files))
+	}
+
+	store, err = NewStore(storePath)
+	if err != nil {
+		t.Fatalf("Want no err, got err: %v", err)
+	}
+	defer store.Close()
+	histories, err := store.List()
+	if len(histories) != 3 || err != nil {
+		t.Errorf("Want 3 histories, got %v (err: %v)", len(histories), err)
+	}
+}
+
+// testStoreURIs returns the URIs of the stores built in
+func testStoreURIs(tempDir string) []string {
+	uris := []string{"json://" + path.Join(tempDir, "histories.json")}
+	if sqliteSupported {
+		uris = append(uris, "sqlite://"+path.Join(tempDir, "histories.db"))
+	}
+	return uris
+}
+
+func createTempDir(t *testing.T) string {
+	tempDir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Fatalf("Cannot create temp. dir.: %v", err)
+	}
+	return tempDir
+}
+
+func cleanup(path string) {
+	_ = os.RemoveAll(path)
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package verify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	awsService        = "sts"
+	awsAlgorithm      = "AWS4-HMAC-SHA256"
+	awsDateTimeFormat = "20060102T150405Z"
+	awsDateFormat     = "20060102"
+	awsRequestBody    = "Action=GetCallerIdentity&Version=2011-06-15"
+	awsContentType    = "application/x-www-form-urlencoded; charset=utf-8"
+)
+
+var (
+	awsKeyIDPattern     = regexp.MustCompile(`\b((?:A3T[A-Z0-9]|AKIA|AGPA|AIDA|AROA|AIPA|ANPA|ANVA|ASIA)[A-Z0-9]{16})\b`)
+	awsSecretKeyPattern = regexp.MustCompile(`(?i)secret_?access_?key["'\x60]?\s{0,50}(?::|=>|=)\s{0,50}["'\x60]?([A-Za-z0-9/+=]{40})\b`)
+
+	// ErrAWSPairNotFound is returned if the content does not contain both an access key ID and a secret access key
+	ErrAWSPairNotFound = errors.New("no access key ID and secret access key pair found")
+
+	// ErrAWSTemporaryKey is returned for temporary access keys, which need a session token
+	ErrAWSTemporaryKey = errors.New("temporary access keys cannot be verified")
+)
+
+// AWSVerifier verifies AWS access keys with STS GetCallerIdentity,
+// which succeeds for any active key regardless of its permissions.
+// The access key ID and the secret access key are looked up together in the content.
+type AWSVerifier struct {
+	Client *http.Client
+	URL    string
+}
+
+// Verify checks if the key pair in the content is accepted by STS
+func (v *AWSVerifier) Verify(ctx context.Context, secret, content string) (bool, error) {
+	keyID, secretKey := findAWSKeyPair(secret, content)
+	if keyID == "" || secretKey == "" {
+		return false, ErrAWSPairNotFound
+	}
+	if strings.HasPrefix(keyID, "ASIA") {
+		return false, ErrAWSTemporaryKey
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(v.URL, "/")+"/", strings.NewReader(awsRequestBody))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", awsContentType)
+	signAWSRequest(req, awsRequestBody, keyID, secretKey, time.Now().UTC())
+
+	return doRequest(v.Client, req, []int{http.StatusOK}, []int{http.StatusForbidden})
+}
+
+// findAWSKeyPair pairs the secret, which is either an access key ID or a secret access key, with its counterpart in the content
+func findAWSKeyPair(secret, content string) (keyID, secretKey string) {
+	if awsKeyIDPattern.MatchString(secret) {
+		keyID = secret
+	} else {
+		secretKey = secret
+	}
+
+	if keyID == "" {
+		if match := awsKeyIDPattern.FindStringSubmatch(content); match != nil {
+			keyID = match[1]
+		}
+	}
+	if secretKey == "" {
+		if match := awsSecretKeyPattern.FindStringSubmatch(content); match != nil {
+			secretKey = match[1]
+		}
+	}
+	return keyID, secretKey
+}
+
+// signAWSRequest adds a Signature Version 4 authorization header to a request with the given body
+func signAWSRequest(req *http.Request, body, keyID, secretKey string, t time.Time) {
+	dateTime := t.Format(awsDateTimeFormat)
+	date := t.Format(awsDateFormat)
+	req.Header.Set("X-Amz-Date", dateTime)
+
+	signedHeaders := "content-type;host;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		"content-type:" + req.Header.Get("Content-Type"),
+		"host:" + req.URL.Host,
+		"x-amz-date:" + dateTime,
+		"",
+		signedHeaders,
+		hashHex(body),
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, AWSRegion, awsService)
+	stringToSign := strings.Join([]string{awsAlgorithm, dateTime, scope, hashHex(canonicalRequest)}, "\n")
+	signature := hex.EncodeToString(hmacSHA256(awsSigningKey(secretKey, date, AWSRegion, awsService), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsAlgorithm, keyID, scope, signedHeaders, signature))
+}
+
+// awsSigningKey derives the Signature Version 4 signing key of a day, region and service
+func awsSigningKey(secretKey, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
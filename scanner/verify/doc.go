@@ -0,0 +1,37 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package verify
+
+import "time"
+
+const (
+	// StatusVerified marks a secret which is still active
+	StatusVerified = "true"
+
+	// StatusUnverified marks a secret which was rejected by its issuer
+	StatusUnverified = "false"
+
+	// StatusUnknown marks a secret whose status could not be determined
+	StatusUnknown = "unknown"
+
+	// DefaultTimeout is the timeout of a verification request
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultAWSSTSURL is the global AWS STS endpoint
+	DefaultAWSSTSURL = "https://sts.amazonaws.com"
+
+	// DefaultSlackURL is the base URL of the Slack Web API
+	DefaultSlackURL = "https://slack.com/api"
+
+	// DefaultGitHubURL is the base URL of the GitHub REST API
+	DefaultGitHubURL = "https://api.github.com"
+
+	// DefaultStripeURL is the base URL of the Stripe API
+	DefaultStripeURL = "https://api.stripe.com"
+
+	// AWSRegion is the region requests to the global STS endpoint are signed for
+	AWSRegion = "us-east-1"
+)
@@ -0,0 +1,31 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package verify
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// GitHubVerifier verifies GitHub personal access tokens by fetching the authenticated user
+type GitHubVerifier struct {
+	Client *http.Client
+	URL    string
+}
+
+// Verify checks if the token is accepted by GitHub
+func (v *GitHubVerifier) Verify(ctx context.Context, secret, content string) (bool, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(v.URL, "/")+"/user", nil)
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "token "+secret)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	return doRequest(v.Client, req, []int{http.StatusOK}, []int{http.StatusUnauthorized})
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// slackInactiveErrors are auth.test errors of tokens which are not usable anymore
+var slackInactiveErrors = map[string]bool{
+	"invalid_auth":     true,
+	"not_authed":       true,
+	"account_inactive": true,
+	"token_revoked":    true,
+	"token_expired":    true,
+}
+
+// SlackVerifier verifies Slack tokens with the auth.test method
+type SlackVerifier struct {
+	Client *http.Client
+	URL    string
+}
+
+type slackAuthTestResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// Verify checks if the token is accepted by Slack
+func (v *SlackVerifier) Verify(ctx context.Context, secret, content string) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(v.URL, "/")+"/auth.test", nil)
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+secret)
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("%v: %s", ErrUnexpectedResponse, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	result := &slackAuthTestResponse{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return false, err
+	}
+
+	if result.OK {
+		return true, nil
+	}
+	if slackInactiveErrors[result.Error] {
+		return false, nil
+	}
+	return false, fmt.Errorf("%v: %s", ErrUnexpectedResponse, result.Error)
+}
@@ -0,0 +1,31 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package verify
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// StripeVerifier verifies Stripe API keys by fetching the account balance
+type StripeVerifier struct {
+	Client *http.Client
+	URL    string
+}
+
+// Verify checks if the key is accepted by Stripe,
+// restricted keys without access to the balance are active nonetheless
+func (v *StripeVerifier) Verify(ctx context.Context, secret, content string) (bool, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(v.URL, "/")+"/v1/balance", nil)
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.SetBasicAuth(secret, "")
+
+	return doRequest(v.Client, req, []int{http.StatusOK, http.StatusForbidden}, []int{http.StatusUnauthorized})
+}
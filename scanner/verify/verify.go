@@ -0,0 +1,162 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package verify
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ErrUnexpectedResponse is returned if an issuer response neither confirms nor rejects a secret
+var ErrUnexpectedResponse = errors.New("unexpected verification response")
+
+// Verifier checks with the issuer of a secret if it is still active
+type Verifier interface {
+	// Verify returns true if the secret is active, false if it was rejected,
+	// and an error if its status could not be determined.
+	// content is the scanned content, for secrets which are only usable together with another value.
+	Verify(ctx context.Context, secret, content string) (bool, error)
+}
+
+// Config contains the issuer endpoints, which can be replaced by stand-ins in tests
+type Config struct {
+	Client    *http.Client
+	AWSSTSURL string
+	SlackURL  string
+	GitHubURL string
+	StripeURL string
+}
+
+// DefaultConfig returns the config of the public issuer endpoints
+func DefaultConfig() Config {
+	return Config{
+		Client:    &http.Client{Timeout: DefaultTimeout},
+		AWSSTSURL: DefaultAWSSTSURL,
+		SlackURL:  DefaultSlackURL,
+		GitHubURL: DefaultGitHubURL,
+		StripeURL: DefaultStripeURL,
+	}
+}
+
+// Registry maps signature IDs to their verifiers and caches verification results
+type Registry struct {
+	mutex     sync.Mutex
+	verifiers map[string]Verifier
+	results   map[string]string
+}
+
+// NewRegistry creates a registry with the built-in verifiers
+func NewRegistry(config Config) *Registry {
+	r := &Registry{
+		verifiers: map[string]Verifier{},
+		results:   map[string]string{},
+	}
+
+	aws := &AWSVerifier{Client: config.Client, URL: config.AWSSTSURL}
+	r.Register("aws-access-key-id", aws)
+	r.Register("aws-access-key-id-value", aws)
+	r.Register("aws-secret-access-key", aws)
+	r.Register("slack-token", &SlackVerifier{Client: config.Client, URL: config.SlackURL})
+	github := &GitHubVerifier{Client: config.Client, URL: config.GitHubURL}
+	r.Register("github-personal-access-token", github)
+	r.Register("github-fine-grained-token", github)
+	r.Register("stripe-api-key", &StripeVerifier{Client: config.Client, URL: config.StripeURL})
+
+	return r
+}
+
+// Register sets the verifier of a signature
+func (r *Registry) Register(signatureID string, verifier Verifier) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.verifiers[signatureID] = verifier
+}
+
+// SignatureIDs returns the sorted IDs of the signatures with a verifier
+func (r *Registry) SignatureIDs() []string {
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var ids []string
+	for id := range r.verifiers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// CanVerify checks if findings of a signature can be verified
+func (r *Registry) CanVerify(signatureID string) bool {
+	if r == nil {
+		return false
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_, ok := r.verifiers[signatureID]
+	return ok
+}
+
+// Verify returns the status of a secret found by a signature, or an empty status if the signature has no verifier.
+// Definite results are cached, so a secret found many times is only verified once.
+func (r *Registry) Verify(ctx context.Context, signatureID, secret, content string) (string, error) {
+	if r == nil || secret == "" {
+		return "", nil
+	}
+
+	r.mutex.Lock()
+	verifier, ok := r.verifiers[signatureID]
+	key := fmt.Sprintf("%s:%x", signatureID, sha256.Sum256([]byte(secret)))
+	status, cached := r.results[key]
+	r.mutex.Unlock()
+	if !ok {
+		return "", nil
+	}
+	if cached {
+		return status, nil
+	}
+
+	active, err := verifier.Verify(ctx, secret, content)
+	if err != nil {
+		return StatusUnknown, err
+	}
+
+	status = StatusUnverified
+	if active {
+		status = StatusVerified
+	}
+	r.mutex.Lock()
+	r.results[key] = status
+	r.mutex.Unlock()
+	return status, nil
+}
+
+// doRequest sends a request and classifies the response status code
+func doRequest(client *http.Client, req *http.Request, activeCodes, inactiveCodes []int) (bool, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	_ = resp.Body.Close()
+
+	for _, code := range activeCodes {
+		if resp.StatusCode == code {
+			return true, nil
+		}
+	}
+	for _, code := range inactiveCodes {
+		if resp.StatusCode == code {
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("%v: %s", ErrUnexpectedResponse, resp.Status)
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package web
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	assetfs "github.com/elazarl/go-bindata-assetfs"
+)
+
+// assets are the embedded frontend files by path
+var assets = map[string]string{
+	"index.html": indexHTML,
+	"app.js":     appJS,
+	"style.css":  styleCSS,
+}
+
+// assetsModTime is reported as modification time of all assets
+var assetsModTime = time.Now()
+
+// Asset returns the content of an embedded file
+func Asset(name string) ([]byte, error) {
+	content, ok := assets[strings.TrimPrefix(name, "/")]
+	if !ok {
+		return nil, fmt.Errorf("asset %s not found", name)
+	}
+	return []byte(content), nil
+}
+
+// AssetDir returns the names of the embedded files in a directory, only the root directory exists
+func AssetDir(name string) ([]string, error) {
+	if strings.Trim(name, "/") != "" {
+		return nil, fmt.Errorf("asset dir %s not found", name)
+	}
+	var names []string
+	for name := range assets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// AssetInfo returns the file info of an embedded file
+func AssetInfo(name string) (os.FileInfo, error) {
+	content, err := Asset(name)
+	if err != nil {
+		return nil, err
+	}
+	return &assetInfo{name: path.Base(name), size: int64(len(content))}, nil
+}
+
+// assetInfo implements os.FileInfo for embedded files
+type assetInfo struct {
+	name string
+	size int64
+}
+
+func (i *assetInfo) Name() string       { return i.name }
+func (i *assetInfo) Size() int64        { return i.size }
+func (i *assetInfo) Mode() os.FileMode  { return 0444 }
+func (i *assetInfo) ModTime() time.Time { return assetsModTime }
+func (i *assetInfo) IsDir() bool        { return false }
+func (i *assetInfo) Sys() interface{}   { return nil }
+
+// binaryFileSystem serves the embedded files with the static middleware
+type binaryFileSystem struct {
+	*assetfs.AssetFS
+}
+
+// Exists checks if a request path is an embedded file or the root directory
+func (b *binaryFileSystem) Exists(prefix string, filepath string) bool {
+	p := strings.TrimPrefix(filepath, prefix)
+	if len(p) == len(filepath) {
+		return false
+	}
+	if strings.Trim(p, "/") == "" {
+		return true
+	}
+	_, err := Asset(p)
+	return err == nil
+}
+
+// BinaryFileSystem returns the file system of the embedded frontend
+func BinaryFileSystem() *binaryFileSystem {
+	return &binaryFileSystem{&assetfs.AssetFS{Asset: Asset, AssetDir: AssetDir, AssetInfo: AssetInfo}}
+}
@@ -0,0 +1,35 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package web
+
+const (
+	// APIPrefix is the path prefix of the REST API
+	APIPrefix = "/api"
+
+	// ContentSecurityPolicy only allows the embedded assets to be loaded
+	ContentSecurityPolicy = "default-src 'self'; style-src 'self'; script-src 'self'; img-src 'self' data:"
+
+	// QueryMinSeverity filters findings with at least this severity
+	QueryMinSeverity = "min_severity"
+
+	// QueryRepository filters findings by repository name
+	QueryRepository = "repository"
+
+	// QueryRule filters findings by rule ID
+	QueryRule = "rule"
+
+	// QueryTag filters findings by rule tag
+	QueryTag = "tag"
+
+	// QueryVerified filters findings by verification status
+	QueryVerified = "verified"
+
+	// QueryText filters findings by a case-insensitive text in path, description or commit
+	QueryText = "q"
+
+	// QueryIgnored returns the ignored findings instead if true
+	QueryIgnored = "ignored"
+)
@@ -0,0 +1,188 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package web
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-contrib/secure"
+	"github.com/gin-contrib/static"
+	"github.com/gin-gonic/gin"
+
+	"github.com/grab/secret-scanner/scanner/findings"
+	"github.com/grab/secret-scanner/scanner/session"
+	"github.com/grab/secret-scanner/scanner/signatures"
+)
+
+// ListenAndServe serves the UI and REST API of a session until the server fails
+func ListenAndServe(sess *session.Session, host, port string) error {
+	if !*sess.Options.Debug {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	return NewRouter(sess, AllowedHosts(host, port)).Run(net.JoinHostPort(host, port))
+}
+
+// AllowedHosts returns the Host headers accepted by a server on a loopback address,
+// which keeps other websites from reading findings by DNS rebinding.
+// Servers on other addresses accept any host.
+func AllowedHosts(host, port string) []string {
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil
+	}
+	return []string{
+		net.JoinHostPort("localhost", port),
+		net.JoinHostPort("127.0.0.1", port),
+		net.JoinHostPort("::1", port),
+	}
+}
+
+// NewRouter creates the router of the UI and REST API of a session
+func NewRouter(sess *session.Session, allowedHosts []string) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery())
+	if *sess.Options.Debug {
+		router.Use(gin.Logger())
+	}
+	router.Use(secure.New(secure.Config{
+		AllowedHosts:          allowedHosts,
+		FrameDeny:             true,
+		ContentTypeNosniff:    true,
+		BrowserXssFilter:      true,
+		ContentSecurityPolicy: ContentSecurityPolicy,
+		ReferrerPolicy:        "no-referrer",
+	}))
+	router.Use(static.Serve("/", BinaryFileSystem()))
+
+	api := router.Group(APIPrefix)
+	api.GET("/stats", statsHandler(sess))
+	api.GET("/findings", findingsHandler(sess))
+	api.GET("/findings/:id", findingHandler(sess))
+	api.POST("/findings/:id/false-positive", requireJSON, falsePositiveHandler(sess))
+	api.GET("/repositories", repositoriesHandler(sess))
+	api.GET("/histories", historiesHandler(sess))
+
+	return router
+}
+
+// requireJSON rejects requests without JSON body, which cannot be sent cross-origin without a CORS preflight
+func requireJSON(c *gin.Context) {
+	if c.ContentType() != gin.MIMEJSON {
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": "content type must be " + gin.MIMEJSON})
+	}
+}
+
+func statsHandler(sess *session.Session) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sess.Stats.Lock()
+		defer sess.Stats.Unlock()
+		c.JSON(http.StatusOK, sess.Stats)
+	}
+}
+
+func findingsHandler(sess *session.Session) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sess.Lock()
+		all := sess.Findings
+		if c.Query(QueryIgnored) == "true" {
+			all = sess.IgnoredFindings
+		}
+		all = append([]*findings.Finding{}, all...)
+		sess.Unlock()
+
+		minSeverity := signatures.SeverityRank(c.Query(QueryMinSeverity))
+		repository := c.Query(QueryRepository)
+		rule := c.Query(QueryRule)
+		tag := c.Query(QueryTag)
+		verified := c.Query(QueryVerified)
+		text := strings.ToLower(c.Query(QueryText))
+
+		matches := []*findings.Finding{}
+		for _, finding := range all {
+			switch {
+			case signatures.SeverityRank(finding.Severity) < minSeverity:
+			case repository != "" && finding.RepositoryName != repository:
+			case rule != "" && finding.SignatureID != rule:
+			case tag != "" && !hasTag(finding, tag):
+			case verified != "" && finding.Verified != verified:
+			case text != "" && !containsText(finding, text):
+			default:
+				matches = append(matches, finding)
+			}
+		}
+		c.JSON(http.StatusOK, matches)
+	}
+}
+
+func findingHandler(sess *session.Session) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		sess.Lock()
+		defer sess.Unlock()
+		for _, list := range [][]*findings.Finding{sess.Findings, sess.IgnoredFindings} {
+			for _, finding := range list {
+				if finding.ID == id {
+					c.JSON(http.StatusOK, finding)
+					return
+				}
+			}
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": session.ErrFindingNotFound.Error()})
+	}
+}
+
+func falsePositiveHandler(sess *session.Session) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		marked, err := sess.MarkFalsePositive(c.Param("id"))
+		switch {
+		case err == session.ErrFindingNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case err != nil:
+			sess.Out.Error("Unable to mark %s as false positive: %s\n", c.Param("id"), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusOK, marked)
+		}
+	}
+}
+
+func repositoriesHandler(sess *session.Session) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sess.Lock()
+		defer sess.Unlock()
+		c.JSON(http.StatusOK, sess.Repositories)
+	}
+}
+
+func historiesHandler(sess *session.Session) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		histories, err := sess.StateStore.List()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, histories)
+	}
+}
+
+func hasTag(finding *findings.Finding, tag string) bool {
+	for _, t := range finding.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func containsText(finding *findings.Finding, text string) bool {
+	for _, field := range []string{finding.FilePath, finding.Description, finding.SignatureID, finding.CommitHash, finding.CommitAuthor} {
+		if strings.Contains(strings.ToLower(field), text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package web
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/grab/secret-scanner/common/log"
+	"github.com/grab/secret-scanner/scanner/findings"
+	"github.com/grab/secret-scanner/scanner/ignore"
+	"github.com/grab/secret-scanner/scanner/session"
+	"github.com/grab/secret-scanner/scanner/state"
+	"github.com/grab/secret-scanner/scanner/stats"
+)
+
+func createTestSession(t *testing.T, dir string) *session.Session {
+	debug := false
+	store, err := state.NewStore("json://" + path.Join(dir, "histories.json"))
+	if err != nil {
+		t.Fatalf("Cannot create state store: %v", err)
+	}
+	sess := &session.Session{
+		Out:            &log.Logger{},
+		Stats:          &stats.Stats{Status: session.StatusAnalyzing},
+		StateStore:     store,
+		IgnoreList:     ignore.NewList(),
+		IgnoreFilePath: path.Join(dir, ignore.DefaultFilename),
+	}
+	sess.Options.Debug = &debug
+	sess.Findings = []*findings.Finding{
+		{ID: "abc123", RepositoryName: "web", SignatureID: "slack-token", Severity: "high", FilePath: "config.js", Tags: []string{"chat"}},
+		{ID: "def456", RepositoryName: "api", SignatureID: "log-file", Severity: "info", FilePath: "debug.log"},
+	}
+	return sess
+}
+
+func performRequest(router http.Handler, method, target, contentType string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader("{}"))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRouter_Findings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tempDir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Errorf("Cannot create temp. dir.: %v", err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+	sess := createTestSession(t, tempDir)
+	defer sess.StateStore.Close()
+	router := NewRouter(sess, nil)
+
+	cases := map[string]int{
+		"/api/findings":                      2,
+		"/api/findings?min_severity=medium":  1,
+		"/api/findings?repository=api":       1,
+		"/api/findings?tag=chat":             1,
+		"/api/findings?q=CONFIG":             1,
+		"/api/findings?rule=aws-account-id":  0,
+		"/api/findings?ignored=true":         0,
+		"/api/findings?verified=true":        0,
+		"/api/findings?min_severity=unknown": 2,
+	}
+	for target, want := range cases {
+		w := performRequest(router, http.MethodGet, target, "")
+		var got []*findings.Finding
+		err := json.Unmarshal(w.Body.Bytes(), &got)
+		if w.Code != http.StatusOK || err != nil || len(got) != want {
+			t.Errorf("Want %v findings for %v, got %v (status %v, err: %v)", want, target, len(got), w.Code, err)
+		}
+	}
+
+	if w := performRequest(router, http.MethodGet, "/api/findings/abc123", ""); w.Code != http.StatusOK {
+		t.Errorf("Want %v, got %v", http.StatusOK, w.Code)
+	}
+	if w := performRequest(router, http.MethodGet, "/api/findings/unknown", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Want %v, got %v", http.StatusNotFound, w.Code)
+	}
+	for _, target := range []string{"/api/stats", "/api/repositories", "/api/histories"} {
+		if w := performRequest(router, http.MethodGet, target, ""); w.Code != http.StatusOK {
+			t.Errorf("Want %v for %v, got %v", http.StatusOK, target, w.Code)
+		}
+	}
+}
+
+func TestRouter_FalsePositive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tempDir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Errorf("Cannot create temp. dir.: %v", err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+	sess := createTestSession(t, tempDir)
+	defer sess.StateStore.Close()
+	router := NewRouter(sess, nil)
+
+	// form posts could be sent by any website
+	w := performRequest(router, http.MethodPost, "/api/findings/abc123/false-positive", "application/x-www-form-urlencoded")
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Want %v, got %v", http.StatusUnsupportedMediaType, w.Code)
+	}
+
+	w = performRequest(router, http.MethodPost, "/api/findings/abc123/false-positive", gin.MIMEJSON)
+	if w.Code != http.StatusOK {
+		t.Errorf("Want %v, got %v", http.StatusOK, w.Code)
+	}
+	if len(sess.Findings) != 1 || len(sess.IgnoredFindings) != 1 {
+		t.Errorf("Want 1 finding and 1 ignored finding, got %v and %v", len(sess.Findings), len(sess.IgnoredFindings))
+	}
+	list, err := ignore.Load(sess.IgnoreFilePath)
+	if err != nil || !list.Fingerprints["abc123"] {
+		t.Errorf("Want fingerprint abc123 in ignore file, got %v, err: %v", list, err)
+	}
+
+	w = performRequest(router, http.MethodPost, "/api/findings/abc123/false-positive", gin.MIMEJSON)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Want %v, got %v", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestRouter_Assets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tempDir, err := ioutil.TempDir("", "ss-test-")
+	if err != nil {
+		t.Errorf("Cannot create temp. dir.: %v", err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+	sess := createTestSession(t, tempDir)
+	defer sess.StateStore.Close()
+	router := NewRouter(sess, AllowedHosts("127.0.0.1", "8080"))
+
+	cases := map[string]string{
+		"/":          "text/html",
+		"/app.js":    "javascript",
+		"/style.css": "text/css",
+	}
+	for target, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Host = "127.0.0.1:8080"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || !strings.Contains(w.Header().Get("Content-Type"), want) {
+			t.Errorf("Want %v for %v, got %v %v", want, target, w.Code, w.Header().Get("Content-Type"))
+		}
+		if w.Header().Get("Content-Security-Policy") != ContentSecurityPolicy {
+			t.Errorf("Want %v, got %v", ContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+		}
+	}
+
+	// other hosts are rejected against DNS rebinding
+	req := httptest.NewRequest(http.MethodGet, "/api/findings", nil)
+	req.Host = "attacker.example:8080"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Want %v, got %v", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestAllowedHosts(t *testing.T) {
+	if hosts := AllowedHosts("0.0.0.0", "8080"); hosts != nil {
+		t.Errorf("Want nil, got %v", hosts)
+	}
+	for _, host := range []string{"127.0.0.1", "localhost", "::1"} {
+		if hosts := AllowedHosts(host, "8080"); len(hosts) != 3 || hosts[0] != "localhost:8080" {
+			t.Errorf("Want 3 loopback hosts for %v, got %v", host, hosts)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+/*
+ * Copyright 2019 Grabtaxi Holdings PTE LTE (GRAB), All rights reserved.
+ * Use of this source code is governed by an MIT-style license that can be found in the LICENSE file
+ */
+
+package web
+
+const indexHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Secret Scanner</title>
+<link rel="stylesheet" href="/style.css">
+</head>
+<body>
+<header>
+  <h1>Secret Scanner</h1>
+  <div id="stats"></div>
+  <div id="progress"><div id="progress-bar"></div></div>
+</header>
+<main>
+  <form id="filters">
+    <select id="filter-severity" title="Minimum severity">
+      <option value="">All severities</option>
+      <option value="critical">Critical</option>
+      <option value="high">High and above</option>
+      <option value="medium">Medium and above</option>
+      <option value="low">Low and above</option>
+    </select>
+    <select id="filter-repository"><option value="">All repositories</option></select>
+    <select id="filter-rule"><option value="">All rules</option></select>
+    <select id="filter-verified">
+      <option value="">Any verification</option>
+      <option value="true">Verified</option>
+      <option value="false">Rejected</option>
+      <option value="unknown">Unknown</option>
+    </select>
+    <input id="filter-text" type="search" placeholder="Path, description, commit or author">
+    <label><input id="filter-ignored" type="checkbox"> Ignored findings</label>
+  </form>
+  <p id="message"></p>
+  <table id="findings">
+    <thead>
+      <tr><th>Severity</th><th>Rule</th><th>Repository</th><th>Location</th><th>Secret</th><th></th></tr>
+    </thead>
+    <tbody></tbody>
+  </table>
+</main>
+<script src="/app.js"></script>
+</body>
+</html>
+`
+
+const styleCSS = `body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #24292e; }
+header { background: #24292e; color: #fff; padding: 1em 2em; }
+header h1 { margin: 0 0 .3em 0; font-size: 1.4em; }
+#stats span { margin-right: 1.5em; }
+#progress { background: #444d56; height: 4px; margin-top: .6em; }
+#progress-bar { background: #28a745; height: 4px; width: 0; }
+main { padding: 1em 2em; }
+#filters { display: flex; flex-wrap: wrap; gap: .5em; align-items: center; margin-bottom: 1em; }
+#filters input[type=search] { min-width: 20em; }
+#message { color: #586069; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .4em .6em; border-bottom: 1px solid #e1e4e8; vertical-align: top; }
+tr.finding { cursor: pointer; }
+tr.finding:hover { background: #f6f8fa; }
+.severity { display: inline-block; border-radius: 3px; padding: 0 .5em; font-size: .8em; font-weight: 600; color: #fff; text-transform: uppercase; }
+.severity-critical { background: #86181d; }
+.severity-high { background: #d73a49; }
+.severity-medium { background: #e36209; }
+.severity-low { background: #0366d6; }
+.severity-info { background: #6a737d; }
+.details { background: #fafbfc; }
+.details dl { display: grid; grid-template-columns: max-content auto; gap: .2em 1em; margin: .5em 0; }
+.details dt { color: #586069; }
+.details dd { margin: 0; word-break: break-all; }
+pre { background: #f6f8fa; border-radius: 6px; padding: .5em 0; overflow-x: auto; font-size: .85em; }
+pre .line { display: block; padding: 0 1em; }
+pre .line.match { background: #fffbdd; }
+pre .number { display: inline-block; min-width: 4em; color: #959da5; }
+button { cursor: pointer; }
+`
+
+const appJS = `(function () {
+  "use strict";
+
+  var filters = {
+    min_severity: document.getElementById("filter-severity"),
+    repository: document.getElementById("filter-repository"),
+    rule: document.getElementById("filter-rule"),
+    verified: document.getElementById("filter-verified"),
+    q: document.getElementById("filter-text")
+  };
+  var ignored = document.getElementById("filter-ignored");
+  var tbody = document.querySelector("#findings tbody");
+  var message = document.getElementById("message");
+  var expanded = {};
+  var finished = false;
+
+  function el(tag, attrs, children) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (key) {
+      node.setAttribute(key, attrs[key]);
+    });
+    (children || []).forEach(function (child) {
+      if (child === null || child === undefined) {
+        return;
+      }
+      node.appendChild(typeof child === "string" ? document.createTextNode(child) : child);
+    });
+    return node;
+  }
+
+  function link(url, text) {
+    if (!/^https?:\/\//.test(url || "")) {
+      return text;
+    }
+    return el("a", {href: url, target: "_blank", rel: "noopener noreferrer"}, [text]);
+  }
+
+  function request(method, path, callback) {
+    var xhr = new XMLHttpRequest();
+    xhr.open(method, path);
+    xhr.onload = function () {
+      var body = null;
+      try {
+        body = JSON.parse(xhr.responseText);
+      } catch (e) {
+        body = null;
+      }
+      if (xhr.status !== 200) {
+        message.textContent = "Request failed: " + ((body && body.error) || xhr.statusText);
+        return;
+      }
+      callback(body);
+    };
+    if (method === "POST") {
+      xhr.setRequestHeader("Content-Type", "application/json");
+      xhr.send("{}");
+    } else {
+      xhr.send();
+    }
+  }
+
+  function updateStats() {
+    request("GET", "/api/stats", function (stats) {
+      var container = document.getElementById("stats");
+      container.textContent = "";
+      [["Status", stats.Status], ["Repositories", stats.Repositories], ["Commits", stats.Commits],
+       ["Files", stats.Files], ["Findings", stats.Findings]].forEach(function (item) {
+        container.appendChild(el("span", {}, [item[0] + ": " + item[1]]));
+      });
+      document.getElementById("progress-bar").style.width = Math.round(stats.Progress || 0) + "%";
+      finished = stats.Status === "finished" || stats.Status === "cancelled";
+    });
+  }
+
+  function addOptions(select, values) {
+    var existing = {};
+    Array.prototype.forEach.call(select.options, function (option) {
+      existing[option.value] = true;
+    });
+    values.sort().forEach(function (value) {
+      if (value && !existing[value]) {
+        select.appendChild(el("option", {value: value}, [value]));
+        existing[value] = true;
+      }
+    });
+  }
+
+  function query() {
+    var params = [];
+    Object.keys(filters).forEach(function (key) {
+      if (filters[key].value) {
+        params.push(key + "=" + encodeURIComponent(filters[key].value));
+      }
+    });
+    if (ignored.checked) {
+      params.push("ignored=true");
+    }
+    return params.length ? "?" + params.join("&") : "";
+  }
+
+  function context(finding) {
+    var lines = finding.ContextLines || [];
+    if (!lines.length) {
+      return finding.LineContent ? el("pre", {}, [el("span", {"class": "line match"}, [finding.LineContent])]) : null;
+    }
+    return el("pre", {}, lines.map(function (line, i) {
+      var number = finding.ContextStartLine + i;
+      return el("span", {"class": number === finding.Line ? "line match" : "line"}, [
+        el("span", {"class": "number"}, [String(number)]), line
+      ]);
+    }));
+  }
+
+  function details(finding) {
+    var fields = [
+      ["Description", finding.Description],
+      ["Comment", finding.Comment],
+      ["Rule", finding.SignatureID],
+      ["Tags", (finding.Tags || []).join(", ")],
+      ["Confidence", finding.Confidence],
+      ["File", link(finding.FileURL, finding.FilePath)],
+      ["Commit", link(finding.CommitURL, finding.CommitHash)],
+      ["Message", finding.CommitMessage],
+      ["Author", finding.CommitAuthor ? finding.CommitAuthor + " <" + finding.CommitAuthorEmail + ">" : ""],
+      ["Date", finding.CommitDate],
+      ["Verified", finding.Verified],
+      ["Status", finding.Status],
+      ["First seen", finding.FirstSeen],
+      ["Secret hash", finding.SecretHash],
+      ["Ignored", finding.IgnoreReason],
+      ["Finding ID", finding.ID]
+    ];
+    var list = el("dl");
+    fields.forEach(function (field) {
+      if (field[1]) {
+        list.appendChild(el("dt", {}, [field[0]]));
+        list.appendChild(el("dd", {}, [field[1]]));
+      }
+    });
+    return el("tr", {"class": "details"}, [el("td", {colspan: "6"}, [list, context(finding)])]);
+  }
+
+  function markFalsePositive(finding, event) {
+    event.stopPropagation();
+    if (!window.confirm("Add " + finding.ID + " to the global ignore file?")) {
+      return;
+    }
+    request("POST", "/api/findings/" + encodeURIComponent(finding.ID) + "/false-positive", function (marked) {
+      message.textContent = "Marked " + marked.length + " finding(s) as false positive.";
+      updateFindings();
+    });
+  }
+
+  function row(finding) {
+    var location = finding.FilePath + (finding.Line ? ":" + finding.Line : "");
+    var action = null;
+    if (!finding.IgnoreReason) {
+      action = el("button", {type: "button"}, ["False positive"]);
+      action.addEventListener("click", markFalsePositive.bind(null, finding));
+    }
+    var tr = el("tr", {"class": "finding"}, [
+      el("td", {}, [el("span", {"class": "severity severity-" + (finding.Severity || "info")}, [finding.Severity || "-"])]),
+      el("td", {}, [finding.SignatureID || finding.Description]),
+      el("td", {}, [finding.RepositoryName]),
+      el("td", {}, [location]),
+      el("td", {}, [finding.SecretMasked || ""]),
+      el("td", {}, [action])
+    ]);
+    tr.addEventListener("click", function () {
+      expanded[finding.ID] = !expanded[finding.ID];
+      render(lastFindings);
+    });
+    return tr;
+  }
+
+  var lastFindings = [];
+
+  function render(findings) {
+    lastFindings = findings;
+    tbody.textContent = "";
+    findings.forEach(function (finding) {
+      tbody.appendChild(row(finding));
+      if (expanded[finding.ID]) {
+        tbody.appendChild(details(finding));
+      }
+    });
+    if (!findings.length) {
+      tbody.appendChild(el("tr", {}, [el("td", {colspan: "6"}, ["No findings."])]));
+    }
+  }
+
+  function updateFindings() {
+    request("GET", "/api/findings" + query(), function (findings) {
+      addOptions(filters.repository, findings.map(function (f) { return f.RepositoryName; }));
+      addOptions(filters.rule, findings.map(function (f) { return f.SignatureID; }));
+      render(findings);
+    });
+  }
+
+  Object.keys(filters).forEach(function (key) {
+    filters[key].addEventListener("change", updateFindings);
+  });
+  filters.q.addEventListener("input", updateFindings);
+  ignored.addEventListener("change", updateFindings);
+  document.getElementById("filters").addEventListener("submit", function (event) {
+    event.preventDefault();
+  });
+
+  updateStats();
+  updateFindings();
+  window.setInterval(function () {
+    if (!finished) {
+      updateStats();
+      updateFindings();
+    }
+  }, 2000);
+})();
+`